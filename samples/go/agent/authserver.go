@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/A2A/samples/go/auth"
+	"github.com/google/A2A/samples/go/rpcserver"
+	"github.com/google/A2A/samples/go/schema"
+	"github.com/google/A2A/samples/go/store"
+)
+
+// AuthOption configures the authentication NewAgentServer enforces in
+// front of its handlers.
+type AuthOption func(*authConfig)
+
+type authConfig struct {
+	verifiers       map[string]auth.Verifier
+	publicAgentCard bool
+}
+
+// WithVerifier registers v to satisfy the scheme named by v.Scheme() in
+// AgentCard.Authentication.Schemes.
+func WithVerifier(v auth.Verifier) AuthOption {
+	return func(c *authConfig) {
+		if c.verifiers == nil {
+			c.verifiers = make(map[string]auth.Verifier)
+		}
+		c.verifiers[v.Scheme()] = v
+	}
+}
+
+// WithPublicAgentCard exempts /agent-card from authentication even when
+// card.Authentication advertises schemes for the rest of the server.
+func WithPublicAgentCard() AuthOption {
+	return func(c *authConfig) { c.publicAgentCard = true }
+}
+
+// NewAgentServer builds the HTTP handler for an agent: tasks/* JSON-RPC
+// requests at /a2a, and card itself at /agent-card. Both are guarded by
+// auth.Middleware against card.Authentication.Schemes, unless opts make
+// /agent-card public or card advertises no schemes at all. /a2a is also
+// wrapped in rpcserver.Recover and rpcserver.CORS, so a handler panic
+// reaches the client as a JSON-RPC error rather than a reset connection,
+// and browser-based clients can call it cross-origin.
+func NewAgentServer(logger *slog.Logger, taskStore store.TaskStore, card schema.AgentCard, opts ...AuthOption) http.Handler {
+	var cfg authConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var schemes []string
+	if card.Authentication != nil {
+		schemes = card.Authentication.Schemes
+	}
+
+	a2aHandler := rpcserver.CORS("*")(rpcserver.Recover(NewA2AHandler(logger, taskStore)))
+	cardHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(card)
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/a2a", auth.Middleware(schemes, cfg.verifiers, a2aHandler))
+	if cfg.publicAgentCard {
+		mux.Handle("/agent-card", cardHandler)
+	} else {
+		mux.Handle("/agent-card", auth.Middleware(schemes, cfg.verifiers, cardHandler))
+	}
+	return mux
+}
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/A2A/samples/go/schema"
+	"github.com/google/A2A/samples/go/store"
+)
+
+// handleTaskCancelMethod adapts cancelTask to rpcserver.MethodHandler
+// for tasks/cancel.
+func (h *A2AHandler) handleTaskCancelMethod(ctx context.Context, req schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+	var params schema.TaskIdParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+	}
+	return h.cancelTask(params)
+}
+
+// cancelTask implements tasks/cancel. It's factored out of
+// handleTaskCancelMethod so NewConnHandler can serve the same method
+// over a jsonrpc.Conn, not just HTTP.
+//
+// It interrupts any in-flight handler via h.taskRunner first — that
+// interruption is fire-and-forget, so this RPC never blocks on the
+// handler noticing — and then transitions the task to Canceled
+// synchronously so the response reflects the new state. A task can be
+// tracked by h.taskRunner before it is visible in h.store (tasks/send
+// only stores it once its handler returns), so the taskRunner is checked
+// even when the store doesn't yet know about the task.
+func (h *A2AHandler) cancelTask(params schema.TaskIdParams) (*schema.Task, *schema.JSONRPCError) {
+	interrupted := h.taskRunner.Cancel(params.ID)
+
+	task, ok := h.store.Get(params.ID)
+	if !ok {
+		if !interrupted {
+			return nil, &schema.JSONRPCError{
+				Code:    -32001,
+				Message: "Task not found",
+				Data:    fmt.Sprintf("Task with ID '%s' not found", params.ID),
+			}
+		}
+		// Tracked by taskRunner but not yet stored: its handler will
+		// observe ctx.Done() and finalize the task itself.
+		return &schema.Task{ID: params.ID, Status: schema.TaskStatus{State: schema.TaskStateCanceled}}, nil
+	}
+
+	if !isTerminalState(task.Status.State) {
+		previousState := task.Status.State
+		task.Status = schema.TaskStatus{State: schema.TaskStateCanceled}
+		h.store.Put(task)
+		h.maybeDispatchPushNotification(task, previousState)
+		h.store.Publish(params.ID, store.TaskEvent{StatusUpdate: &schema.TaskStatusUpdateEvent{
+			ID:     params.ID,
+			Status: task.Status,
+			Final:  true,
+		}})
+	}
+	return task, nil
+}
+
+// isTerminalState reports whether state is one a task cannot leave.
+func isTerminalState(state schema.TaskState) bool {
+	switch state {
+	case schema.TaskStateCompleted, schema.TaskStateCanceled, schema.TaskStateFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// terminalStatusForContextErr maps a run's ctx.Err() to the TaskStatus it
+// should end in: a deadline expiry is reported as Canceled with a message
+// explaining why, while an explicit cancel is reported as plain Canceled.
+func terminalStatusForContextErr(err error) schema.TaskStatus {
+	if err == context.DeadlineExceeded {
+		timeoutMsg := schema.Message{
+			Role:  "agent",
+			Parts: []schema.Part{schema.TextPart{Type: "text", Text: "Task deadline exceeded"}},
+		}
+		return schema.TaskStatus{State: schema.TaskStateCanceled, Message: &timeoutMsg}
+	}
+	return schema.TaskStatus{State: schema.TaskStateCanceled}
+}
@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/A2A/samples/go/jsonrpc"
+	"github.com/google/A2A/samples/go/schema"
+	"github.com/google/A2A/samples/go/store"
+)
+
+// NewConnHandler adapts h's methods to jsonrpc.Handler, so a jsonrpc.Conn
+// over a duplex transport (WebSocket, stdio) can serve them with the same
+// logic ServeHTTP uses for HTTP POST. tasks/sendSubscribe and
+// tasks/resubscribe push their events as "tasks/event" Notify calls on
+// conn instead of SSE frames — the mechanism that lets an agent call back
+// into its client mid-task, which a one-shot HTTP POST can't do.
+func NewConnHandler(h *A2AHandler) jsonrpc.Handler {
+	return func(ctx context.Context, conn *jsonrpc.Conn, req *schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+		logger := h.logger.With(slog.String("method", req.Method), slog.Any("id", req.ID))
+
+		switch req.Method {
+		case "tasks/get":
+			var params schema.TaskQueryParams
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+			}
+			return h.getTask(params)
+		case "tasks/cancel":
+			var params schema.TaskIdParams
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+			}
+			return h.cancelTask(params)
+		case "tasks/send":
+			var params schema.TaskSendParams
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+			}
+			return h.sendTask(params, logger)
+		case "tasks/sendSubscribe":
+			var params schema.TaskSendParams
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+			}
+			events, cancel, rpcErr := h.sendTaskSubscribe(params, logger)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			defer cancel()
+			return h.streamConnNotify(ctx, conn, events)
+		case "tasks/resubscribe":
+			var params schema.TaskIdParams
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+			}
+			events, cancel, rpcErr := h.resubscribe(params, logger)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			defer cancel()
+			return h.streamConnNotify(ctx, conn, events)
+		default:
+			return nil, &schema.JSONRPCError{
+				Code:    -32601,
+				Message: "Method not found",
+				Data:    fmt.Sprintf("Method '%s' not supported over this connection", req.Method),
+			}
+		}
+	}
+}
+
+// streamConnNotify delivers each event from events to conn exactly once:
+// non-final events go out as "tasks/event" Notify calls as they happen,
+// and the last, Final event is instead returned as the request's own
+// result, the jsonrpc.Conn counterpart to streamSSE's final SSE frame.
+// Notifying the Final event too would deliver it twice — once to a
+// "tasks/event" handler, once as the Call's return value — for a peer
+// that, not unreasonably given the two are documented as parallel feeds,
+// reacts to both.
+func (h *A2AHandler) streamConnNotify(ctx context.Context, conn *jsonrpc.Conn, events <-chan store.TaskEvent) (interface{}, *schema.JSONRPCError) {
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return nil, nil
+			}
+			var result interface{}
+			if ev.StatusUpdate != nil {
+				result = ev.StatusUpdate
+			} else {
+				result = ev.ArtifactUpdate
+			}
+			if ev.Final() {
+				return result, nil
+			}
+			if err := conn.Notify(ctx, "tasks/event", result); err != nil {
+				return nil, &schema.JSONRPCError{Code: -32603, Message: "Internal error", Data: err.Error()}
+			}
+		case <-ctx.Done():
+			return nil, &schema.JSONRPCError{Code: -32000, Message: "Request canceled", Data: ctx.Err().Error()}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/A2A/samples/go/jsonrpc"
+	"github.com/google/A2A/samples/go/schema"
+	"github.com/google/A2A/samples/go/store"
+)
+
+// pipeConns wires a client and server jsonrpc.Conn over an in-memory
+// duplex pipe and starts both Run loops, mirroring jsonrpc_test.go's
+// helper of the same shape.
+func pipeConns(t *testing.T, serverHandler, clientHandler jsonrpc.Handler) (client, server *jsonrpc.Conn, closeConns func()) {
+	t.Helper()
+	clientRWC, serverRWC := net.Pipe()
+
+	server = jsonrpc.NewConn(jsonrpc.NewStream(serverRWC))
+	client = jsonrpc.NewConn(jsonrpc.NewStream(clientRWC))
+
+	go server.Run(context.Background(), serverHandler)
+	go client.Run(context.Background(), clientHandler)
+
+	return client, server, func() {
+		clientRWC.Close()
+		serverRWC.Close()
+	}
+}
+
+func TestNewConnHandlerTasksSendAndGet(t *testing.T) {
+	taskStore := store.NewInMemoryTaskStore()
+	h := NewA2AHandler(slog.Default(), taskStore)
+	client, _, closeConns := pipeConns(t, NewConnHandler(h), nil)
+	defer closeConns()
+
+	var sent schema.Task
+	if err := client.Call(context.Background(), "tasks/send", schema.TaskSendParams{ID: "t1", Message: schema.Message{Role: "user"}}, &sent); err != nil {
+		t.Fatalf("tasks/send: %v", err)
+	}
+	if sent.Status.State != schema.TaskStateCompleted {
+		t.Fatalf("tasks/send status = %q, want completed (echo handler completes immediately)", sent.Status.State)
+	}
+
+	var got schema.Task
+	if err := client.Call(context.Background(), "tasks/get", schema.TaskQueryParams{TaskIdParams: schema.TaskIdParams{ID: "t1"}}, &got); err != nil {
+		t.Fatalf("tasks/get: %v", err)
+	}
+	if got.ID != "t1" {
+		t.Fatalf("tasks/get returned %+v, want ID t1", got)
+	}
+}
+
+// TestNewConnHandlerSendSubscribePushesNotifications proves
+// tasks/sendSubscribe calls back into the client mid-request: the client's
+// Call doesn't return until the task reaches a Final state, but it must
+// observe at least one "tasks/event" Notify from the server before that —
+// something a single HTTP POST/response can't do.
+func TestNewConnHandlerSendSubscribePushesNotifications(t *testing.T) {
+	taskStore := store.NewInMemoryTaskStore()
+	h := NewA2AHandler(slog.Default(), taskStore)
+
+	var mu sync.Mutex
+	var notifications []string
+	clientHandler := func(ctx context.Context, conn *jsonrpc.Conn, req *schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+		mu.Lock()
+		notifications = append(notifications, req.Method)
+		mu.Unlock()
+		return nil, nil
+	}
+
+	client, _, closeConns := pipeConns(t, NewConnHandler(h), clientHandler)
+	defer closeConns()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var final schema.TaskStatusUpdateEvent
+	if err := client.Call(ctx, "tasks/sendSubscribe", schema.TaskSendParams{ID: "t2", Message: schema.Message{Role: "user"}}, &final); err != nil {
+		t.Fatalf("tasks/sendSubscribe: %v", err)
+	}
+	if !final.Final {
+		t.Fatalf("final result %+v, want Final true", final)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(notifications) == 0 {
+		t.Fatal("client received no tasks/event notifications before sendSubscribe's Call returned")
+	}
+	for _, m := range notifications {
+		if m != "tasks/event" {
+			t.Errorf("notification method = %q, want tasks/event", m)
+		}
+	}
+	// echoStreamHandler sends one non-final "working" update before its
+	// Final "completed" one; the latter must arrive only as final's
+	// return value above, not also as a tasks/event Notify.
+	if len(notifications) != 1 {
+		t.Errorf("got %d tasks/event notifications, want 1 (the Final event must not be double-delivered)", len(notifications))
+	}
+}
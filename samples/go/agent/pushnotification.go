@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// maybeDispatchPushNotification delivers task to its registered push
+// notification config, if any, but only when its state actually moved on
+// from previousState — repeated events for an unchanged state (e.g. an
+// artifact update mid-"working") don't re-trigger delivery.
+func (h *A2AHandler) maybeDispatchPushNotification(task *schema.Task, previousState schema.TaskState) {
+	if task.Status.State == previousState {
+		return
+	}
+	cfg, ok := h.store.GetPushConfig(task.ID)
+	if !ok {
+		return
+	}
+	go h.pushDispatcher.Notify(context.Background(), cfg, task)
+}
+
+// handleSetPushNotificationMethod adapts setPushConfig to
+// rpcserver.MethodHandler for tasks/pushNotification/set.
+func (h *A2AHandler) handleSetPushNotificationMethod(ctx context.Context, req schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+	var params schema.TaskPushNotificationConfig
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+	}
+	return h.setPushConfig(params)
+}
+
+// setPushConfig implements tasks/pushNotification/set: it registers cfg
+// to receive push notifications for an existing task.
+func (h *A2AHandler) setPushConfig(params schema.TaskPushNotificationConfig) (*schema.TaskPushNotificationConfig, *schema.JSONRPCError) {
+	if _, ok := h.store.Get(params.ID); !ok {
+		return nil, &schema.JSONRPCError{
+			Code:    -32001,
+			Message: "Task not found",
+			Data:    fmt.Sprintf("Task with ID '%s' not found", params.ID),
+		}
+	}
+
+	h.store.SetPushConfig(params.ID, params.PushNotificationConfig)
+	return &params, nil
+}
+
+// handleGetPushNotificationMethod adapts getPushConfig to
+// rpcserver.MethodHandler for tasks/pushNotification/get.
+func (h *A2AHandler) handleGetPushNotificationMethod(ctx context.Context, req schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+	var params schema.TaskIdParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+	}
+	return h.getPushConfig(params)
+}
+
+// getPushConfig implements tasks/pushNotification/get's lookup.
+func (h *A2AHandler) getPushConfig(params schema.TaskIdParams) (*schema.TaskPushNotificationConfig, *schema.JSONRPCError) {
+	cfg, ok := h.store.GetPushConfig(params.ID)
+	if !ok {
+		return nil, &schema.JSONRPCError{
+			Code:    -32002,
+			Message: "Push notification not supported",
+			Data:    fmt.Sprintf("No push notification config registered for task '%s'", params.ID),
+		}
+	}
+	return &schema.TaskPushNotificationConfig{ID: params.ID, PushNotificationConfig: cfg}, nil
+}
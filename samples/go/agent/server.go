@@ -1,29 +1,77 @@
 package agent
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/google/A2A/samples/go/pushnotify"
+	"github.com/google/A2A/samples/go/rpcserver"
 	"github.com/google/A2A/samples/go/schema"
 	"github.com/google/A2A/samples/go/store"
 )
 
+// TaskHandler processes a tasks/send request's message synchronously and
+// returns the resulting task. message is params.Message; task is
+// pre-populated with ID, SessionID and Metadata from the request.
+type TaskHandler func(ctx context.Context, task *schema.Task, message *schema.Message) (*schema.Task, error)
+
+// echoTaskHandler is the synchronous counterpart to echoStreamHandler: it
+// immediately completes the task by echoing the input parts back.
+func echoTaskHandler(ctx context.Context, task *schema.Task, message *schema.Message) (*schema.Task, error) {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	responseMessage := schema.Message{
+		Role:     "agent",
+		Parts:    message.Parts, // Echo back the input parts
+		Metadata: map[string]interface{}{"echo_response": true},
+	}
+	task.Status = schema.TaskStatus{
+		State:     schema.TaskStateCompleted, // Immediately complete
+		Message:   &responseMessage,
+		Timestamp: &now,
+	}
+	return task, nil
+}
+
 // A2AHandler handles A2A protocol requests.
 type A2AHandler struct {
-	logger *slog.Logger
-	store  store.TaskStore
+	logger         *slog.Logger
+	store          store.TaskStore
+	taskHandler    TaskHandler
+	streamHandler  TaskStreamHandler
+	pushDispatcher *pushnotify.Dispatcher
+	taskRunner     *TaskRunner
+	metrics        *rpcserver.Metrics
+
+	// router serves A2AHandler's synchronous, non-streaming methods
+	// other than tasks/send, which ServeHTTP dispatches to
+	// handleTaskSend itself. The SSE-driven tasks/sendSubscribe and
+	// tasks/resubscribe aren't registered on it either: they need
+	// direct access to the ResponseWriter to stream a response instead
+	// of returning one value the router could encode for them.
+	router *rpcserver.MethodRouter
 }
 
 // NewA2AHandler creates a new A2AHandler.
 func NewA2AHandler(logger *slog.Logger, store store.TaskStore) *A2AHandler {
-	return &A2AHandler{
-		logger: logger,
-		store:  store,
-	}
+	h := &A2AHandler{
+		logger:         logger,
+		store:          store,
+		taskHandler:    echoTaskHandler,
+		streamHandler:  echoStreamHandler{},
+		pushDispatcher: pushnotify.NewDispatcher(logger, pushnotify.Config{}),
+		taskRunner:     NewTaskRunner(),
+		metrics:        rpcserver.NewMetrics(),
+	}
+	h.router = rpcserver.NewMethodRouter(logger, h.metrics)
+	h.router.Handle("tasks/get", h.handleTaskGetMethod)
+	h.router.Handle("tasks/cancel", h.handleTaskCancelMethod)
+	h.router.Handle("tasks/pushNotification/set", h.handleSetPushNotificationMethod)
+	h.router.Handle("tasks/pushNotification/get", h.handleGetPushNotificationMethod)
+	return h
 }
 
 // ServeHTTP handles incoming HTTP requests.
@@ -34,21 +82,9 @@ func (h *A2AHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		h.writeJSONRPCError(w, nil, -32700, "Parse error", err.Error())
-		return
-	}
-	defer r.Body.Close()
-
-	var req schema.JSONRPCRequest
-	if err := json.Unmarshal(body, &req); err != nil {
-		h.writeJSONRPCError(w, nil, -32700, "Parse error", err.Error())
-		return
-	}
-
-	if req.JSONRPC != "2.0" {
-		h.writeJSONRPCError(w, req.ID, -32600, "Invalid Request", "Invalid JSON-RPC version")
+	req, rpcErr := rpcserver.DecodeRequest(r)
+	if rpcErr != nil {
+		rpcserver.JSONError(w, req.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data)
 		return
 	}
 
@@ -58,12 +94,12 @@ func (h *A2AHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch req.Method {
 	case "tasks/send":
 		h.handleTaskSend(w, req, requestLogger)
-	case "tasks/get":
-		h.handleTaskGet(w, req, requestLogger)
+	case "tasks/sendSubscribe":
+		h.handleTaskSendSubscribe(w, r, req, requestLogger)
+	case "tasks/resubscribe":
+		h.handleTaskResubscribe(w, r, req, requestLogger)
 	default:
-		errMsg := fmt.Sprintf("Method '%s' not supported", req.Method)
-		requestLogger.Warn("Method not found", slog.String("error", errMsg))
-		h.writeJSONRPCError(w, req.ID, -32601, "Method not found", errMsg)
+		h.router.ServeMethod(w, r, req)
 	}
 }
 
@@ -72,112 +108,99 @@ func (h *A2AHandler) handleTaskSend(w http.ResponseWriter, req schema.JSONRPCReq
 	var params schema.TaskSendParams
 	if err := json.Unmarshal(req.Params, &params); err != nil {
 		logger.Error("Invalid params for tasks/send", slog.String("error", err.Error()))
-		h.writeJSONRPCError(w, req.ID, -32602, "Invalid params", err.Error())
+		rpcserver.JSONError(w, req.ID, -32602, "Invalid params", err.Error())
+		return
+	}
+
+	task, rpcErr := h.sendTask(params, logger)
+	if rpcErr != nil {
+		rpcserver.JSONError(w, req.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data)
 		return
 	}
+	rpcserver.JSONResult(w, req.ID, task)
+}
 
+// sendTask implements tasks/send: it runs params through h.taskHandler to
+// completion and records the result. It's factored out of handleTaskSend
+// so NewConnHandler can serve the same method over a jsonrpc.Conn, not
+// just HTTP.
+func (h *A2AHandler) sendTask(params schema.TaskSendParams, logger *slog.Logger) (*schema.Task, *schema.JSONRPCError) {
 	logger = logger.With(slog.String("task_id", params.ID))
 
-	// --- Basic Echo Logic ---
-	now := time.Now().UTC().Format(time.RFC3339Nano)
-	responseMessage := schema.Message{
-		Role:     "agent",
-		// IMPORTANT: This is just an echo. A real agent would process the input
-		// (params.Message.Parts) and construct meaningful output parts here.
-		Parts:    params.Message.Parts, // Echo back the input parts
-		Metadata: map[string]interface{}{"echo_response": true},
-	}
 	task := &schema.Task{
 		ID:        params.ID,
 		SessionID: params.SessionID,
-		Status: schema.TaskStatus{
-			State:     schema.TaskStateCompleted, // Immediately complete
-			Message:   &responseMessage,
-			Timestamp: &now,
-		},
-		Metadata: params.Metadata, // Echo metadata
+		Status:    schema.TaskStatus{State: schema.TaskStateSubmitted},
+		Metadata:  params.Metadata, // Echo metadata
 	}
-	// ------------------------
-
-	h.store.Put(task)
-	logger.Info("Task created and completed")
 
-	resp := schema.JSONRPCResponse{
-		JSONRPCMessage: schema.JSONRPCMessage{
-			JSONRPCMessageIdentifier: schema.JSONRPCMessageIdentifier{ID: req.ID},
-			JSONRPC:                  "2.0",
-		},
-		Result: task, // Return the completed task object
+	ctx, done, err := h.taskRunner.Start(context.Background(), params.ID, runnerMetadata(params))
+	if err != nil {
+		logger.Error("Invalid params for tasks/send", slog.String("error", err.Error()))
+		return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
 	}
-	h.writeJSONResponse(w, resp, logger)
-}
+	defer done()
 
-// handleTaskGet processes tasks/get requests.
-func (h *A2AHandler) handleTaskGet(w http.ResponseWriter, req schema.JSONRPCRequest, logger *slog.Logger) {
-	var params schema.TaskQueryParams
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		logger.Error("Invalid params for tasks/get", slog.String("error", err.Error()))
-		h.writeJSONRPCError(w, req.ID, -32602, "Invalid params", err.Error())
-		return
+	task, err = h.taskHandler(ctx, task, &params.Message)
+	if err != nil {
+		logger.Error("Task handler failed", slog.String("error", err.Error()))
+		return nil, &schema.JSONRPCError{Code: -32603, Message: "Internal error", Data: err.Error()}
 	}
 
-	logger = logger.With(slog.String("task_id", params.ID))
+	if ctxErr := ctx.Err(); ctxErr != nil && !isTerminalState(task.Status.State) {
+		task.Status = terminalStatusForContextErr(ctxErr)
+	}
 
-	task, found := h.store.Get(params.ID)
-	if !found {
-		errMsg := fmt.Sprintf("Task with ID '%s' not found", params.ID)
-		logger.Warn("Task not found", slog.String("error", errMsg))
-		h.writeJSONRPCError(w, req.ID, -32001, "Task not found", errMsg)
-		return
+	h.store.Put(task)
+	h.appendHistory(params.ID, params.Message)
+	if task.Status.Message != nil {
+		h.appendHistory(params.ID, *task.Status.Message)
 	}
+	h.maybeDispatchPushNotification(task, schema.TaskStateSubmitted)
+	logger.Info("Task created and completed")
 
-	logger.Info("Retrieved task")
+	return task, nil
+}
 
-	resp := schema.JSONRPCResponse{
-		JSONRPCMessage: schema.JSONRPCMessage{
-			JSONRPCMessageIdentifier: schema.JSONRPCMessageIdentifier{ID: req.ID},
-			JSONRPC:                  "2.0",
-		},
-		Result: task, // Return the found task object
+// appendHistory records msg to task id's history, logging rather than
+// failing the request if the store can't append it.
+func (h *A2AHandler) appendHistory(id string, msg schema.Message) {
+	if err := h.store.AppendHistory(id, msg); err != nil {
+		h.logger.Error("Failed to append task history", slog.String("task_id", id), slog.String("error", err.Error()))
 	}
-	h.writeJSONResponse(w, resp, logger)
 }
 
-// writeJSONResponse sends a JSON response.
-func (h *A2AHandler) writeJSONResponse(w http.ResponseWriter, resp interface{}, logger *slog.Logger) {
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		logger.Error("Error encoding JSON response", slog.String("error", err.Error()))
-		// Attempt to write a minimal error if encoding fails, but might also fail.
-		http.Error(w, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error"},"id":null}`, http.StatusInternalServerError)
+// handleTaskGetMethod adapts getTask to rpcserver.MethodHandler for
+// tasks/get.
+func (h *A2AHandler) handleTaskGetMethod(ctx context.Context, req schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+	var params schema.TaskQueryParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
 	}
+	return h.getTask(params)
 }
 
-// writeJSONRPCError sends a JSON-RPC error response.
-func (h *A2AHandler) writeJSONRPCError(w http.ResponseWriter, id *interface{}, code int, message string, data interface{}) {
-	rpcErr := schema.JSONRPCError{
-		Code:    code,
-		Message: message,
-		Data:    data,
-	}
-	resp := schema.JSONRPCResponse{
-		JSONRPCMessage: schema.JSONRPCMessage{
-			JSONRPCMessageIdentifier: schema.JSONRPCMessageIdentifier{ID: id},
-			JSONRPC:                  "2.0",
-		},
-		Error: &rpcErr,
-	}
-
-	h.logger.Warn("Sending error response",
-		slog.Int("code", code),
-		slog.String("message", message),
-		slog.Any("data", data),
-		slog.Any("id", id),
-	)
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError) // Often appropriate for RPC errors
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		h.logger.Error("Error encoding JSON-RPC error response", slog.String("error", err.Error()))
-	}
-}
\ No newline at end of file
+// getTask implements tasks/get's lookup. It's factored out of
+// handleTaskGetMethod so NewConnHandler can serve the same method over a
+// jsonrpc.Conn, not just HTTP.
+func (h *A2AHandler) getTask(params schema.TaskQueryParams) (*schema.Task, *schema.JSONRPCError) {
+	task, found := h.store.Get(params.ID)
+	if !found {
+		return nil, &schema.JSONRPCError{
+			Code:    -32001,
+			Message: "Task not found",
+			Data:    fmt.Sprintf("Task with ID '%s' not found", params.ID),
+		}
+	}
+
+	if params.HistoryLength != nil && *params.HistoryLength > 0 {
+		history, err := h.store.History(params.ID, *params.HistoryLength)
+		if err != nil {
+			return nil, &schema.JSONRPCError{Code: -32603, Message: "Internal error", Data: err.Error()}
+		}
+		taskWithHistory := *task
+		taskWithHistory.History = history
+		task = &taskWithHistory
+	}
+	return task, nil
+}
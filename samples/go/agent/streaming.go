@@ -0,0 +1,296 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/A2A/samples/go/rpcserver"
+	"github.com/google/A2A/samples/go/schema"
+	"github.com/google/A2A/samples/go/store"
+)
+
+// streamHeartbeatInterval is how often a ": heartbeat" SSE comment is
+// written on an otherwise idle stream so intermediate proxies don't time
+// the connection out.
+const streamHeartbeatInterval = 15 * time.Second
+
+// TaskStreamHandler runs a task end-to-end, emitting incremental
+// schema.TaskStatusUpdateEvent and schema.TaskArtifactUpdateEvent values on
+// sink as work progresses. The last event sent must carry Final status.
+type TaskStreamHandler interface {
+	Stream(ctx context.Context, params schema.TaskSendParams, sink chan<- store.TaskEvent)
+}
+
+// echoStreamHandler is the streaming counterpart to echoTaskHandler: it
+// reports one "working" update before echoing the input back as the
+// completed result.
+type echoStreamHandler struct{}
+
+func (echoStreamHandler) Stream(ctx context.Context, params schema.TaskSendParams, sink chan<- store.TaskEvent) {
+	send := func(ev store.TaskEvent) bool {
+		select {
+		case sink <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	workingMsg := schema.Message{
+		Role:     "agent",
+		Parts:    []schema.Part{schema.TextPart{Type: "text", Text: "Processing..."}},
+		Metadata: map[string]interface{}{"echo_response": true},
+	}
+	workingTimestamp := time.Now().UTC().Format(time.RFC3339Nano)
+	if !send(store.TaskEvent{StatusUpdate: &schema.TaskStatusUpdateEvent{
+		ID: params.ID,
+		Status: schema.TaskStatus{
+			State:     schema.TaskStateWorking,
+			Message:   &workingMsg,
+			Timestamp: &workingTimestamp,
+		},
+	}}) {
+		return
+	}
+
+	completedMsg := schema.Message{
+		Role:     "agent",
+		Parts:    params.Message.Parts, // Echo back the input parts
+		Metadata: map[string]interface{}{"echo_response": true},
+	}
+	completedTimestamp := time.Now().UTC().Format(time.RFC3339Nano)
+	send(store.TaskEvent{StatusUpdate: &schema.TaskStatusUpdateEvent{
+		ID: params.ID,
+		Status: schema.TaskStatus{
+			State:     schema.TaskStateCompleted,
+			Message:   &completedMsg,
+			Timestamp: &completedTimestamp,
+		},
+		Final: true,
+	}})
+}
+
+// runTaskStream drives h.streamHandler to completion, applying every
+// status/artifact update to h.store and publishing it to h.store's
+// subscribers (the initiating tasks/sendSubscribe caller and any
+// tasks/resubscribe callers alike). It is independent of any single HTTP
+// request's lifetime so a client disconnect doesn't abort the task. If
+// ctx is canceled or its deadline expires before the handler reaches a
+// terminal state on its own, runTaskStream synthesizes one so every
+// stream still ends with a Final event.
+func (h *A2AHandler) runTaskStream(ctx context.Context, params schema.TaskSendParams) {
+	sink := make(chan store.TaskEvent)
+	go func() {
+		defer close(sink)
+		h.streamHandler.Stream(ctx, params, sink)
+	}()
+
+	for ev := range sink {
+		switch {
+		case ev.StatusUpdate != nil:
+			su := ev.StatusUpdate
+			task, ok := h.store.Get(su.ID)
+			if !ok {
+				task = &schema.Task{ID: su.ID, SessionID: params.SessionID, Metadata: params.Metadata}
+			}
+			previousState := task.Status.State
+			task.Status = su.Status
+			h.store.Put(task)
+			if su.Status.Message != nil {
+				h.appendHistory(su.ID, *su.Status.Message)
+			}
+			h.maybeDispatchPushNotification(task, previousState)
+		case ev.ArtifactUpdate != nil:
+			au := ev.ArtifactUpdate
+			if task, ok := h.store.Get(au.ID); ok {
+				task.Artifacts = append(task.Artifacts, au.Artifact)
+				h.store.Put(task)
+			}
+		}
+		h.store.Publish(params.ID, ev)
+	}
+
+	if err := ctx.Err(); err != nil {
+		h.finalizeCanceledOrTimedOut(params, err)
+	}
+}
+
+// finalizeCanceledOrTimedOut synthesizes and publishes the terminal event
+// for a stream that stopped because of ctx, mapping a deadline expiry to
+// Canceled with a timeout message and an explicit cancel to plain
+// Canceled. It
+// consults the live store state first and does nothing if the task is
+// already terminal — tasks/cancel may have raced it there first.
+func (h *A2AHandler) finalizeCanceledOrTimedOut(params schema.TaskSendParams, err error) {
+	task, ok := h.store.Get(params.ID)
+	if ok && isTerminalState(task.Status.State) {
+		return
+	}
+	if !ok {
+		task = &schema.Task{ID: params.ID, SessionID: params.SessionID, Metadata: params.Metadata}
+	}
+	previousState := task.Status.State
+	status := terminalStatusForContextErr(err)
+	task.Status = status
+	h.store.Put(task)
+	h.maybeDispatchPushNotification(task, previousState)
+	h.store.Publish(params.ID, store.TaskEvent{StatusUpdate: &schema.TaskStatusUpdateEvent{
+		ID:     params.ID,
+		Status: status,
+		Final:  true,
+	}})
+}
+
+// handleTaskSendSubscribe processes tasks/sendSubscribe requests: it starts
+// a task runner and streams its events back as they happen.
+func (h *A2AHandler) handleTaskSendSubscribe(w http.ResponseWriter, r *http.Request, req schema.JSONRPCRequest, logger *slog.Logger) {
+	var params schema.TaskSendParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		logger.Error("Invalid params for tasks/sendSubscribe", slog.String("error", err.Error()))
+		rpcserver.JSONError(w, req.ID, -32602, "Invalid params", err.Error())
+		return
+	}
+
+	events, cancel, rpcErr := h.sendTaskSubscribe(params, logger)
+	if rpcErr != nil {
+		rpcserver.JSONError(w, req.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+		return
+	}
+	defer cancel()
+
+	h.streamSSE(w, r, req.ID, events, logger)
+}
+
+// sendTaskSubscribe implements tasks/sendSubscribe's setup: recording the
+// task as submitted, starting its runner, and subscribing to its events.
+// It's factored out of handleTaskSendSubscribe so NewConnHandler can drive
+// the same stream over a jsonrpc.Conn, pushing events as Notify calls
+// instead of SSE frames.
+func (h *A2AHandler) sendTaskSubscribe(params schema.TaskSendParams, logger *slog.Logger) (<-chan store.TaskEvent, func(), *schema.JSONRPCError) {
+	logger = logger.With(slog.String("task_id", params.ID))
+
+	h.store.Put(&schema.Task{
+		ID:        params.ID,
+		SessionID: params.SessionID,
+		Status:    schema.TaskStatus{State: schema.TaskStateSubmitted},
+		Metadata:  params.Metadata,
+	})
+	h.appendHistory(params.ID, params.Message)
+
+	events, cancel := h.store.Subscribe(params.ID)
+
+	ctx, done, err := h.taskRunner.Start(context.Background(), params.ID, runnerMetadata(params))
+	if err != nil {
+		logger.Error("Invalid params for tasks/sendSubscribe", slog.String("error", err.Error()))
+		cancel()
+		return nil, nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+	}
+	go func() {
+		defer done()
+		h.runTaskStream(ctx, params)
+	}()
+
+	logger.Info("Streaming task started")
+	return events, cancel, nil
+}
+
+// handleTaskResubscribe processes tasks/resubscribe requests, attaching a
+// new listener to an in-flight or already-decided task's event stream.
+func (h *A2AHandler) handleTaskResubscribe(w http.ResponseWriter, r *http.Request, req schema.JSONRPCRequest, logger *slog.Logger) {
+	var params schema.TaskIdParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		logger.Error("Invalid params for tasks/resubscribe", slog.String("error", err.Error()))
+		rpcserver.JSONError(w, req.ID, -32602, "Invalid params", err.Error())
+		return
+	}
+
+	events, cancel, rpcErr := h.resubscribe(params, logger)
+	if rpcErr != nil {
+		rpcserver.JSONError(w, req.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data)
+		return
+	}
+	defer cancel()
+
+	h.streamSSE(w, r, req.ID, events, logger)
+}
+
+// resubscribe implements tasks/resubscribe: attaching a new listener to an
+// in-flight or already-decided task's event stream. It's factored out of
+// handleTaskResubscribe so NewConnHandler can serve the same method over a
+// jsonrpc.Conn.
+func (h *A2AHandler) resubscribe(params schema.TaskIdParams, logger *slog.Logger) (<-chan store.TaskEvent, func(), *schema.JSONRPCError) {
+	logger = logger.With(slog.String("task_id", params.ID))
+
+	if _, ok := h.store.Get(params.ID); !ok {
+		errMsg := fmt.Sprintf("Task with ID '%s' not found", params.ID)
+		logger.Warn("Task not found", slog.String("error", errMsg))
+		return nil, nil, &schema.JSONRPCError{Code: -32001, Message: "Task not found", Data: errMsg}
+	}
+
+	events, cancel := h.store.Subscribe(params.ID)
+	logger.Info("Resubscribed to task")
+	return events, cancel, nil
+}
+
+// streamSSE upgrades w to text/event-stream and writes each event from
+// events as a framed JSON-RPC response carrying reqID, flushing after
+// every write. It sends a heartbeat comment on idle streams and returns
+// when events closes, a final event is seen, or the client disconnects.
+func (h *A2AHandler) streamSSE(w http.ResponseWriter, r *http.Request, reqID *interface{}, events <-chan store.TaskEvent, logger *slog.Logger) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		rpcserver.JSONError(w, reqID, -32603, "Internal error", "streaming not supported by this transport")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			var result interface{}
+			if ev.StatusUpdate != nil {
+				result = ev.StatusUpdate
+			} else {
+				result = ev.ArtifactUpdate
+			}
+			resp := schema.JSONRPCResponse{
+				JSONRPCMessage: schema.JSONRPCMessage{
+					JSONRPCMessageIdentifier: schema.JSONRPCMessageIdentifier{ID: reqID},
+					JSONRPC:                  "2.0",
+				},
+				Result: result,
+			}
+			b, err := json.Marshal(resp)
+			if err != nil {
+				logger.Error("Error encoding SSE frame", slog.String("error", err.Error()))
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", b)
+			flusher.Flush()
+			if ev.Final() {
+				return
+			}
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			logger.Info("Client disconnected from stream")
+			return
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// run holds the cancel func for one in-flight task, so TaskRunner.Start
+// and an eventual done() call can agree on which run they're tearing
+// down even if the task ID gets reused for a later Start before done()
+// runs (comparing the *run pointer, not just the map key).
+type run struct {
+	cancel context.CancelFunc
+}
+
+// TaskRunner tracks per-task cancellation state so a tasks/cancel RPC (or
+// an expired deadline) can interrupt an in-flight handler without the RPC
+// itself blocking on that handler returning. Deadlines are plain
+// context.WithDeadline contexts, so ctx.Err() already distinguishes an
+// explicit cancel (context.Canceled) from a deadline expiry
+// (context.DeadlineExceeded) for free.
+type TaskRunner struct {
+	mu   sync.Mutex
+	runs map[string]*run
+}
+
+// NewTaskRunner creates an empty TaskRunner.
+func NewTaskRunner() *TaskRunner {
+	return &TaskRunner{runs: make(map[string]*run)}
+}
+
+// deadlineMetadataKey is the optional TaskSendParams.Metadata entry
+// (RFC3339 timestamp) that arms an automatic deadline for the run.
+const deadlineMetadataKey = "deadline"
+
+// runnerMetadata returns the metadata TaskRunner.Start should see for
+// params: params.Metadata as-is, unless params.Deadline or
+// params.TimeoutMs resolves to a deadline, in which case a copy is
+// returned with deadlineMetadataKey set accordingly. It never mutates
+// params.Metadata itself, since that map is also echoed back to the
+// caller as the stored task's Metadata.
+func runnerMetadata(params schema.TaskSendParams) map[string]interface{} {
+	deadline := params.Deadline
+	if deadline == nil && params.TimeoutMs != nil {
+		d := time.Now().Add(time.Duration(*params.TimeoutMs) * time.Millisecond).Format(time.RFC3339Nano)
+		deadline = &d
+	}
+	if deadline == nil {
+		return params.Metadata
+	}
+
+	metadata := make(map[string]interface{}, len(params.Metadata)+1)
+	for k, v := range params.Metadata {
+		metadata[k] = v
+	}
+	metadata[deadlineMetadataKey] = *deadline
+	return metadata
+}
+
+// Start begins tracking taskID's in-flight run, returning a context
+// derived from parent that is canceled by Cancel(taskID) or, if
+// metadata["deadline"] is a valid RFC3339 timestamp, when that deadline
+// passes. Callers must invoke the returned done func (typically deferred)
+// once the handler returns, to release the runner's bookkeeping.
+func (tr *TaskRunner) Start(parent context.Context, taskID string, metadata map[string]interface{}) (ctx context.Context, done func(), err error) {
+	var cancelFn context.CancelFunc
+	if raw, ok := metadata[deadlineMetadataKey]; ok {
+		s, ok := raw.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf(`agent: metadata["deadline"] must be an RFC3339 string`)
+		}
+		deadline, parseErr := time.Parse(time.RFC3339, s)
+		if parseErr != nil {
+			return nil, nil, fmt.Errorf("agent: invalid deadline: %w", parseErr)
+		}
+		ctx, cancelFn = context.WithDeadline(parent, deadline)
+	} else {
+		ctx, cancelFn = context.WithCancel(parent)
+	}
+
+	r := &run{cancel: cancelFn}
+	tr.mu.Lock()
+	tr.runs[taskID] = r
+	tr.mu.Unlock()
+
+	done = func() {
+		tr.mu.Lock()
+		if tr.runs[taskID] == r {
+			delete(tr.runs, taskID)
+		}
+		tr.mu.Unlock()
+		cancelFn()
+	}
+
+	return ctx, done, nil
+}
+
+// Cancel cancels the in-flight run for taskID, if any, and reports
+// whether one was found. It returns immediately; it never waits for the
+// handler to observe the cancellation.
+func (tr *TaskRunner) Cancel(taskID string) bool {
+	tr.mu.Lock()
+	r, ok := tr.runs[taskID]
+	tr.mu.Unlock()
+	if !ok {
+		return false
+	}
+	r.cancel()
+	return true
+}
@@ -0,0 +1,189 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// waitDone fails the test if ctx isn't canceled within a short timeout.
+func waitDone(t *testing.T, ctx context.Context) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not canceled in time")
+	}
+}
+
+func TestTaskRunnerCancelBeforeStart(t *testing.T) {
+	tr := NewTaskRunner()
+
+	if tr.Cancel("task-1") {
+		t.Fatal("Cancel on an untracked task should report false")
+	}
+
+	ctx, done, err := tr.Start(context.Background(), "task-1", nil)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer done()
+
+	select {
+	case <-ctx.Done():
+		t.Fatal("context should not be canceled until Cancel is called")
+	default:
+	}
+}
+
+func TestTaskRunnerCancelMidFlight(t *testing.T) {
+	tr := NewTaskRunner()
+
+	ctx, done, err := tr.Start(context.Background(), "task-2", nil)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer done()
+
+	if !tr.Cancel("task-2") {
+		t.Fatal("Cancel should find the in-flight run")
+	}
+	waitDone(t, ctx)
+	if ctx.Err() != context.Canceled {
+		t.Fatalf("ctx.Err() = %v, want context.Canceled", ctx.Err())
+	}
+}
+
+func TestTaskRunnerCancelAfterDone(t *testing.T) {
+	tr := NewTaskRunner()
+
+	_, done, err := tr.Start(context.Background(), "task-3", nil)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	done()
+
+	if tr.Cancel("task-3") {
+		t.Fatal("Cancel should not find a run whose done() already ran")
+	}
+}
+
+func TestTaskRunnerDeadlineExpires(t *testing.T) {
+	tr := NewTaskRunner()
+	deadline := time.Now().Add(20 * time.Millisecond).Format(time.RFC3339Nano)
+
+	ctx, done, err := tr.Start(context.Background(), "task-4", map[string]interface{}{
+		"deadline": deadline,
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer done()
+
+	waitDone(t, ctx)
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Fatalf("ctx.Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+}
+
+func TestTaskRunnerDeadlineExpiryRaceWithExplicitCancel(t *testing.T) {
+	tr := NewTaskRunner()
+	deadline := time.Now().Add(10 * time.Millisecond).Format(time.RFC3339Nano)
+
+	ctx, done, err := tr.Start(context.Background(), "task-5", map[string]interface{}{
+		"deadline": deadline,
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer done()
+
+	// Racing an explicit Cancel against the deadline expiring must not
+	// panic regardless of which wins.
+	time.Sleep(5 * time.Millisecond)
+	tr.Cancel("task-5")
+	waitDone(t, ctx)
+}
+
+func TestTaskRunnerInvalidDeadline(t *testing.T) {
+	tr := NewTaskRunner()
+
+	if _, _, err := tr.Start(context.Background(), "task-6", map[string]interface{}{
+		"deadline": "not-a-timestamp",
+	}); err == nil {
+		t.Fatal("expected an error for a malformed deadline")
+	}
+
+	// Start should have cleaned up after itself, so a later Start with the
+	// same task ID works normally.
+	if _, _, err := tr.Start(context.Background(), "task-6", nil); err != nil {
+		t.Fatalf("Start after a failed Start: %v", err)
+	}
+}
+
+func TestRunnerMetadataFromTimeoutMs(t *testing.T) {
+	timeoutMs := 20
+	params := schema.TaskSendParams{ID: "task-8", TimeoutMs: &timeoutMs}
+
+	tr := NewTaskRunner()
+	ctx, done, err := tr.Start(context.Background(), params.ID, runnerMetadata(params))
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer done()
+
+	waitDone(t, ctx)
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Fatalf("ctx.Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+}
+
+func TestRunnerMetadataDeadlineTakesPrecedenceOverTimeoutMs(t *testing.T) {
+	farTimeoutMs := 60_000
+	nearDeadline := time.Now().Add(20 * time.Millisecond).Format(time.RFC3339Nano)
+	params := schema.TaskSendParams{ID: "task-9", Deadline: &nearDeadline, TimeoutMs: &farTimeoutMs}
+
+	tr := NewTaskRunner()
+	ctx, done, err := tr.Start(context.Background(), params.ID, runnerMetadata(params))
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer done()
+
+	waitDone(t, ctx)
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Fatalf("ctx.Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+}
+
+func TestRunnerMetadataLeavesCallerMetadataUntouched(t *testing.T) {
+	deadline := time.Now().Add(time.Hour).Format(time.RFC3339Nano)
+	callerMetadata := map[string]interface{}{"foo": "bar"}
+	params := schema.TaskSendParams{ID: "task-10", Deadline: &deadline, Metadata: callerMetadata}
+
+	runnerMetadata(params)
+
+	if _, ok := callerMetadata["deadline"]; ok {
+		t.Fatal("runnerMetadata must not mutate the caller's Metadata map")
+	}
+}
+
+func TestTaskRunnerPastDeadlineCancelsImmediately(t *testing.T) {
+	tr := NewTaskRunner()
+	past := time.Now().Add(-time.Minute).Format(time.RFC3339Nano)
+
+	ctx, done, err := tr.Start(context.Background(), "task-7", map[string]interface{}{
+		"deadline": past,
+	})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer done()
+
+	waitDone(t, ctx)
+	if ctx.Err() != context.DeadlineExceeded {
+		t.Fatalf("ctx.Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+}
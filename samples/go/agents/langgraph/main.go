@@ -13,6 +13,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/A2A/samples/go/rpcserver"
+	"github.com/google/A2A/samples/go/schema"
 	"github.com/google/a2a/samples/go/common/server"
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/option"
@@ -23,6 +25,13 @@ type CurrencyAgent struct {
 	llm       *genai.Client
 	exchanger *ExchangeRateTool
 	router    *gin.Engine
+
+	// rpcRouter dispatches tasks/send the same way rpcserver.MethodRouter
+	// does for Island A's A2AHandler; router mounts it at POST "/" via
+	// gin.WrapH. tasks/sendSubscribe isn't registered on it, matching
+	// A2AHandler: it needs direct access to the ResponseWriter to stream
+	// a response instead of returning one value the router could encode.
+	rpcRouter *rpcserver.MethodRouter
 }
 
 func NewCurrencyAgent() *CurrencyAgent {
@@ -54,6 +63,8 @@ func NewCurrencyAgent() *CurrencyAgent {
 		exchanger:   NewExchangeRateTool(),
 		router:      router,
 	}
+	agent.rpcRouter = rpcserver.NewMethodRouter(nil, nil)
+	agent.rpcRouter.Handle("tasks/send", agent.handleTaskSendMethod)
 
 	// Setup CORS
 	router.Use(func(c *gin.Context) {
@@ -68,7 +79,7 @@ func NewCurrencyAgent() *CurrencyAgent {
 	})
 
 	// Define routes
-	router.POST("/", agent.handleRequest)
+	router.POST("/", gin.WrapH(http.HandlerFunc(agent.serveA2A)))
 	router.GET("/.well-known/agent.json", agent.getAgentCard)
 
 	return agent
@@ -91,11 +102,18 @@ func NewExchangeRateTool() *ExchangeRateTool {
 	}
 }
 
-func (t *ExchangeRateTool) GetExchangeRate(from, to string) (*CurrencyResponse, error) {
+// GetExchangeRate fetches the exchange rate from from to to. If date is
+// empty, it fetches the latest rate; otherwise date must be a
+// YYYY-MM-DD historical date.
+func (t *ExchangeRateTool) GetExchangeRate(from, to, date string) (*CurrencyResponse, error) {
 	from, to = strings.ToUpper(from), strings.ToUpper(to)
-	url := fmt.Sprintf("https://api.frankfurter.app/latest?from=%s&to=%s", from, to)
+	endpoint := "latest"
+	if date != "" {
+		endpoint = date
+	}
+	url := fmt.Sprintf("https://api.frankfurter.app/%s?from=%s&to=%s", endpoint, from, to)
 
-	log.Printf("Fetching exchange rate from %s to %s...", from, to)
+	log.Printf("Fetching exchange rate from %s to %s (%s)...", from, to, endpoint)
 	resp, err := t.client.Get(url)
 	if err != nil {
 		return nil, fmt.Errorf("request error: %w", err)
@@ -119,90 +137,283 @@ func (t *ExchangeRateTool) GetExchangeRate(from, to string) (*CurrencyResponse,
 	return &result, nil
 }
 
-func (a *CurrencyAgent) handleRequest(c *gin.Context) {
-	var request map[string]interface{}
-	if err := c.BindJSON(&request); err != nil {
-		c.JSON(400, Response{
-			JSONRPC: "2.0",
-			ID:      "",
-			Error: &Error{
-				Code:    -32700,
-				Message: "Parse error",
-			},
-		})
+// serveA2A implements http.Handler so router can mount it via gin.WrapH,
+// decoding the request through rpcserver the same way A2AHandler's
+// ServeHTTP does for Island A: tasks/sendSubscribe is special-cased for
+// its own streaming response, and every other method (just tasks/send,
+// here) is dispatched through a.rpcRouter.
+func (a *CurrencyAgent) serveA2A(w http.ResponseWriter, r *http.Request) {
+	req, rpcErr := rpcserver.DecodeRequest(r)
+	if rpcErr != nil {
+		rpcserver.JSONError(w, req.ID, rpcErr.Code, rpcErr.Message, rpcErr.Data)
 		return
 	}
 
-	// Handle different methods
-	method, ok := request["method"].(string)
-	if !ok {
-		c.JSON(400, Response{
-			JSONRPC: "2.0",
-			ID:      request["id"].(string),
-			Error: &Error{
-				Code:    -32600,
-				Message: "Invalid Request",
-			},
-		})
+	if req.Method == "tasks/sendSubscribe" {
+		a.handleTaskSendSubscribe(w, r, req)
 		return
 	}
+	a.rpcRouter.ServeMethod(w, r, req)
+}
 
-	switch method {
-	case "tasks/send", "tasks/sendSubscribe":
-		a.handleTask(c, request)
-	default:
-		c.JSON(400, Response{
-			JSONRPC: "2.0",
-			ID:      request["id"].(string),
-			Error: &Error{
-				Code:    -32601,
-				Message: "Method not found",
+// geminiModelName is the Gemini model CurrencyAgent drives its
+// tool-calling loop with.
+const geminiModelName = "gemini-1.5-flash"
+
+// maxToolCallRounds bounds runAgentLoop in case the model keeps calling
+// tools without ever settling on a final text turn.
+const maxToolCallRounds = 5
+
+// exchangeRateFunctionName is the function name declared to Gemini for
+// ExchangeRateTool.GetExchangeRate.
+const exchangeRateFunctionName = "get_exchange_rate"
+
+// exchangeRateGenaiTool declares ExchangeRateTool.GetExchangeRate to
+// Gemini so the model can request it as a function call. It's static, so
+// runAgentLoop shares this one value across calls rather than rebuilding
+// it per request.
+var exchangeRateGenaiTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{{
+		Name:        exchangeRateFunctionName,
+		Description: "Get the exchange rate between two currencies, optionally as of a historical date.",
+		Parameters: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"from": {Type: genai.TypeString, Description: "The currency to convert from, as an ISO 4217 code (e.g. USD)."},
+				"to":   {Type: genai.TypeString, Description: "The currency to convert to, as an ISO 4217 code (e.g. EUR)."},
+				"date": {Type: genai.TypeString, Description: "Optional historical date in YYYY-MM-DD form; omit for the latest rate."},
 			},
-		})
+			Required: []string{"from", "to"},
+		},
+	}},
+}
+
+// runAgentLoop drives a Gemini tool-calling conversation for text to a
+// final text answer, invoking a.exchanger for every get_exchange_rate
+// call the model makes and feeding the results back as
+// genai.FunctionResponse parts. onToolCall, if non-nil, is invoked before
+// each round of tool dispatch so a caller streaming progress
+// (handleTaskSendSubscribe) can emit an intermediate frame; sendTask's
+// synchronous response passes nil.
+func (a *CurrencyAgent) runAgentLoop(ctx context.Context, text string, onToolCall func()) (string, error) {
+	model := a.llm.GenerativeModel(geminiModelName)
+	model.Tools = []*genai.Tool{exchangeRateGenaiTool}
+	session := model.StartChat()
+
+	resp, err := session.SendMessage(ctx, genai.Text(text))
+	if err != nil {
+		return "", fmt.Errorf("gemini: send message: %w", err)
+	}
+
+	for round := 0; round < maxToolCallRounds; round++ {
+		calls, final, ok := responseContent(resp)
+		if !ok {
+			return "", fmt.Errorf("gemini: empty response")
+		}
+		if len(calls) == 0 {
+			return final, nil
+		}
+
+		if onToolCall != nil {
+			onToolCall()
+		}
+
+		responses := make([]genai.Part, len(calls))
+		for i, call := range calls {
+			result, err := a.dispatchFunctionCall(call)
+			if err != nil {
+				return "", err
+			}
+			responses[i] = genai.FunctionResponse{Name: call.Name, Response: result}
+		}
+
+		resp, err = session.SendMessage(ctx, responses...)
+		if err != nil {
+			return "", fmt.Errorf("gemini: send function response: %w", err)
+		}
 	}
+	return "", fmt.Errorf("gemini: exceeded %d tool-call rounds without a final answer", maxToolCallRounds)
 }
 
-func (a *CurrencyAgent) handleTask(c *gin.Context, request map[string]interface{}) {
-	params, ok := request["params"].(map[string]interface{})
-	if !ok {
-		c.JSON(400, Response{
-			JSONRPC: "2.0",
-			ID:      request["id"].(string),
-			Error: &Error{
-				Code:    -32602,
-				Message: "Invalid params",
+// responseContent extracts every function call from resp, if the model
+// made any, or its concatenated text otherwise. A response mixing
+// function calls with text is treated as calls-only, since the calls
+// must be answered before the model will continue. ok is false only when
+// resp carries no usable content at all.
+func responseContent(resp *genai.GenerateContentResponse) (calls []*genai.FunctionCall, text string, ok bool) {
+	if resp == nil || len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return nil, "", false
+	}
+	var sb strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		switch p := part.(type) {
+		case genai.FunctionCall:
+			call := p
+			calls = append(calls, &call)
+		case genai.Text:
+			sb.WriteString(string(p))
+		}
+	}
+	if len(calls) > 0 {
+		return calls, "", true
+	}
+	return nil, sb.String(), true
+}
+
+// dispatchFunctionCall invokes the tool call requests and returns the
+// result to feed back to Gemini as a genai.FunctionResponse.
+func (a *CurrencyAgent) dispatchFunctionCall(call *genai.FunctionCall) (map[string]interface{}, error) {
+	if call.Name != exchangeRateFunctionName {
+		return nil, fmt.Errorf("gemini: unknown function %q", call.Name)
+	}
+
+	from, _ := call.Args["from"].(string)
+	to, _ := call.Args["to"].(string)
+	date, _ := call.Args["date"].(string)
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("get_exchange_rate: from and to are required")
+	}
+
+	result, err := a.exchanger.GetExchangeRate(from, to, date)
+	if err != nil {
+		return nil, fmt.Errorf("get_exchange_rate: %w", err)
+	}
+	return map[string]interface{}{
+		"amount": result.Amount,
+		"base":   result.Base,
+		"date":   result.Date,
+		"rates":  result.Rates,
+	}, nil
+}
+
+// handleTaskSendMethod adapts sendTask to rpcserver.MethodHandler for
+// registration on a.rpcRouter.
+func (a *CurrencyAgent) handleTaskSendMethod(ctx context.Context, req schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+	var params schema.TaskSendParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, &schema.JSONRPCError{Code: -32602, Message: "Invalid params", Data: err.Error()}
+	}
+	return a.sendTask(ctx, params)
+}
+
+// sendTask runs params.Message through the Gemini tool-calling loop to
+// completion, reporting a Failed task instead of a JSON-RPC error if the
+// loop itself fails.
+func (a *CurrencyAgent) sendTask(ctx context.Context, params schema.TaskSendParams) (*schema.Task, *schema.JSONRPCError) {
+	text := extractText(params.Message)
+
+	reply, err := a.runAgentLoop(ctx, text, nil)
+	state := schema.TaskStateCompleted
+	replyText := reply
+	if err != nil {
+		log.Printf("agent loop failed: %v", err)
+		state = schema.TaskStateFailed
+		replyText = err.Error()
+	}
+
+	return &schema.Task{
+		ID:        params.ID,
+		SessionID: params.SessionID,
+		Status: schema.TaskStatus{
+			State: state,
+			Message: &schema.Message{
+				Role:  "agent",
+				Parts: []schema.Part{schema.TextPart{Type: "text", Text: replyText}},
 			},
-		})
+		},
+	}, nil
+}
+
+// extractText pulls the first TextPart's text out of msg, the only part
+// type CurrencyAgent's Gemini loop understands.
+func extractText(msg schema.Message) string {
+	for _, p := range msg.Parts {
+		if tp, ok := p.(schema.TextPart); ok {
+			return tp.Text
+		}
+	}
+	return ""
+}
+
+// sseHeartbeatInterval is how often a ": heartbeat" SSE comment is written
+// on an otherwise idle tasks/sendSubscribe connection so intermediate
+// proxies don't time it out.
+const sseHeartbeatInterval = 15 * time.Second
+
+// handleTaskSendSubscribe processes tasks/sendSubscribe by upgrading the
+// response to text/event-stream and driving workingEvent/completedEvent/
+// failedEvent frames through writeSSE as the task runs, ending with a
+// Final frame. The stream closes early if the client disconnects.
+//
+// CurrencyAgent doesn't persist tasks, so unlike Island A's A2AHandler
+// there is no per-task event history for a tasks/resubscribe caller to
+// attach to after the fact.
+func (a *CurrencyAgent) handleTaskSendSubscribe(w http.ResponseWriter, r *http.Request, req schema.JSONRPCRequest) {
+	var params schema.TaskSendParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		rpcserver.JSONError(w, req.ID, -32602, "Invalid params", err.Error())
 		return
 	}
+	text := extractText(params.Message)
 
-	message, ok := params["message"].(map[string]interface{})
+	flusher, ok := w.(http.Flusher)
 	if !ok {
-		c.JSON(400, errorResponse(request["id"].(string), "Invalid message"))
+		rpcserver.JSONError(w, req.ID, -32603, "Internal error", "streaming not supported by this transport")
 		return
 	}
 
-	// Process the message and return appropriate response
-	// For now, just echo back a simple response
-	c.JSON(200, Response{
-		JSONRPC: "2.0",
-		ID:      request["id"].(string),
-		Result: map[string]interface{}{
-			"id": params["id"],
-			"status": map[string]interface{}{
-				"state": "completed",
-				"message": map[string]interface{}{
-					"role": "agent",
-					"parts": []map[string]interface{}{
-						{
-							"type": "text",
-							"text": "Echo: " + message["text"].(string),
-						},
-					},
-				},
-			},
-		},
-	})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	writeSSE(w, flusher, req.ID, workingEvent(params.ID))
+
+	// runAgentLoop runs on its own goroutine since it calls out to Gemini
+	// and the exchange rate API; the select loop below stays free to
+	// heartbeat and to notice a client disconnect while it waits. Every
+	// write to w happens on this goroutine, never the one running the
+	// loop, so there's no concurrent-write race on the ResponseWriter.
+	// Sends to working/final race a ctx.Done so the loop's goroutine
+	// isn't left blocked forever if the client disconnects first.
+	ctx := r.Context()
+	working := make(chan struct{})
+	final := make(chan *schema.TaskStatusUpdateEvent, 1)
+	go func() {
+		reply, err := a.runAgentLoop(ctx, text, func() {
+			select {
+			case working <- struct{}{}:
+			case <-ctx.Done():
+			}
+		})
+
+		ev := completedEvent(params.ID, reply)
+		if err != nil {
+			ev = failedEvent(params.ID, err.Error())
+		}
+		select {
+		case final <- ev:
+		case <-ctx.Done():
+		}
+	}()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-working:
+			writeSSE(w, flusher, req.ID, workingEvent(params.ID))
+		case ev := <-final:
+			writeSSE(w, flusher, req.ID, ev)
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		case <-ctx.Done():
+			return
+		}
+	}
 }
 
 func (a *CurrencyAgent) getAgentCard(c *gin.Context) {
@@ -211,8 +422,8 @@ func (a *CurrencyAgent) getAgentCard(c *gin.Context) {
 		"description": "An agent that helps with currency conversions",
 		"version":     "1.0.0",
 		"capabilities": map[string]interface{}{
-			"streaming":             true,
-			"pushNotifications":     false,
+			"streaming":              true,
+			"pushNotifications":      false,
 			"stateTransitionHistory": true,
 		},
 		"defaultInputModes":  []string{"text"},
@@ -220,70 +431,62 @@ func (a *CurrencyAgent) getAgentCard(c *gin.Context) {
 	})
 }
 
-func workingPayload(id string) map[string]interface{} {
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"result": map[string]interface{}{
-			"id": id,
-			"status": map[string]interface{}{
-				"state": "working",
-				"message": map[string]interface{}{
-					"role": "agent",
-					"parts": []map[string]interface{}{{
-						"type": "text",
-						"text": "Let me help you with that...",
-					}},
-				},
-				"timestamp": time.Now(),
+func workingEvent(id string) *schema.TaskStatusUpdateEvent {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	return &schema.TaskStatusUpdateEvent{
+		ID: id,
+		Status: schema.TaskStatus{
+			State: schema.TaskStateWorking,
+			Message: &schema.Message{
+				Role:  "agent",
+				Parts: []schema.Part{schema.TextPart{Type: "text", Text: "Let me help you with that..."}},
 			},
+			Timestamp: &now,
 		},
 	}
 }
 
-func completedPayload(id, txt string) map[string]interface{} {
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"result": map[string]interface{}{
-			"id": id,
-			"status": map[string]interface{}{
-				"state": "completed",
-				"message": map[string]interface{}{
-					"role": "agent",
-					"parts": []map[string]interface{}{{
-						"type": "text",
-						"text": txt,
-					}},
-				},
-				"timestamp": time.Now(),
-			},
+func completedEvent(id, txt string) *schema.TaskStatusUpdateEvent {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	return &schema.TaskStatusUpdateEvent{
+		ID: id,
+		Status: schema.TaskStatus{
+			State:     schema.TaskStateCompleted,
+			Message:   &schema.Message{Role: "agent", Parts: []schema.Part{schema.TextPart{Type: "text", Text: txt}}},
+			Timestamp: &now,
 		},
-		"final": true,
+		Final: true,
 	}
 }
 
-func failurePayload(id, errMsg string) map[string]interface{} {
-	return map[string]interface{}{
-		"jsonrpc": "2.0",
-		"result": map[string]interface{}{
-			"id": id,
-			"status": map[string]interface{}{
-				"state": "failed",
-				"message": map[string]interface{}{
-					"role": "agent",
-					"parts": []map[string]interface{}{{
-						"type": "text",
-						"text": errMsg,
-					}},
-				},
-				"timestamp": time.Now(),
-			},
+func failedEvent(id, errMsg string) *schema.TaskStatusUpdateEvent {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	return &schema.TaskStatusUpdateEvent{
+		ID: id,
+		Status: schema.TaskStatus{
+			State:     schema.TaskStateFailed,
+			Message:   &schema.Message{Role: "agent", Parts: []schema.Part{schema.TextPart{Type: "text", Text: errMsg}}},
+			Timestamp: &now,
 		},
-		"final": true,
+		Final: true,
 	}
 }
 
-func writeSSE(w http.ResponseWriter, f http.Flusher, data interface{}) {
-	b, _ := json.Marshal(data)
+// writeSSE marshals ev as the JSON-RPC result for reqID and writes it as
+// one SSE frame, the same framing agent/streaming.go's streamSSE uses.
+func writeSSE(w http.ResponseWriter, f http.Flusher, reqID *interface{}, ev *schema.TaskStatusUpdateEvent) {
+	resp := schema.JSONRPCResponse{
+		JSONRPCMessage: schema.JSONRPCMessage{
+			JSONRPCMessageIdentifier: schema.JSONRPCMessageIdentifier{ID: reqID},
+			JSONRPC:                  "2.0",
+		},
+		Result: ev,
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("encode SSE frame: %v", err)
+		return
+	}
 	fmt.Fprintf(w, "data: %s\n\n", b)
 	f.Flush()
 }
@@ -298,28 +501,3 @@ func main() {
 	log.Printf("Starting Currency Exchange Agent on http://%s", addr)
 	log.Fatal(http.ListenAndServe(addr, agent))
 }
-
-// Response represents the JSON-RPC response structure
-type Response struct {
-	JSONRPC string       `json:"jsonrpc"`
-	ID      string       `json:"id"`
-	Result  interface{} `json:"result,omitempty"`
-	Error   *Error      `json:"error,omitempty"`
-}
-
-// Error represents an error in the JSON-RPC response
-type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
-
-func errorResponse(id, errMsg string) Response {
-	return Response{
-		JSONRPC: "2.0",
-		ID:      id,
-		Error: &Error{
-			Code:    -32602,
-			Message: errMsg,
-		},
-	}
-}
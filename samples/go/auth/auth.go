@@ -0,0 +1,95 @@
+// Package auth provides http.Handler middleware that enforces the
+// authentication schemes an AgentCard advertises, so a request must
+// satisfy at least one of Bearer, Basic, or OIDC before it reaches the
+// A2A handler.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// ErrorCodeUnauthorized is the JSON-RPC error code returned when a
+// request doesn't satisfy any of the card's advertised authentication
+// schemes.
+const ErrorCodeUnauthorized = -32004
+
+// Principal identifies the caller an incoming request was authenticated
+// as. Task handlers can read it back via PrincipalFromContext.
+type Principal struct {
+	Subject string
+	Scheme  string
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the Principal that middleware
+// authenticated ctx's request as, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Verifier authenticates one scheme's credentials out of an incoming
+// request. A Verifier plugs into Middleware under the scheme name its
+// Scheme method returns, which must match an entry in
+// AgentAuthentication.Schemes for it to ever run.
+type Verifier interface {
+	Scheme() string
+	Verify(r *http.Request) (Principal, error)
+}
+
+// Middleware wraps next so a request must satisfy at least one of
+// schemes before it's let through. Schemes are tried in order; the
+// first Verifier (looked up in verifiers by scheme name) that accepts
+// the request wins, and its Principal is attached to the request's
+// context for next. A scheme with no registered Verifier is skipped.
+// A request satisfying none of schemes gets a JSON-RPC
+// ErrorCodeUnauthorized response instead of reaching next.
+//
+// len(schemes) == 0 means the card advertises no authentication, so
+// every request is let through unauthenticated.
+func Middleware(schemes []string, verifiers map[string]Verifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(schemes) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		lastErr := fmt.Errorf("auth: no verifier registered for any of the advertised schemes %v", schemes)
+		for _, scheme := range schemes {
+			v, ok := verifiers[scheme]
+			if !ok {
+				continue
+			}
+			principal, err := v.Verify(r)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+			return
+		}
+
+		writeUnauthorized(w, lastErr)
+	})
+}
+
+func writeUnauthorized(w http.ResponseWriter, cause error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	w.WriteHeader(http.StatusUnauthorized)
+	resp := schema.JSONRPCResponse{
+		JSONRPCMessage: schema.JSONRPCMessage{JSONRPC: "2.0"},
+		Error: &schema.JSONRPCError{
+			Code:    ErrorCodeUnauthorized,
+			Message: "Unauthorized",
+			Data:    cause.Error(),
+		},
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,145 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/google/A2A/samples/go/auth"
+)
+
+func bearerRequest(token string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/a2a", nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
+
+func TestBearerVerifierStaticTokenRotation(t *testing.T) {
+	v := auth.NewBearerVerifier([]string{"old-token"})
+
+	if _, err := v.Verify(bearerRequest("old-token")); err != nil {
+		t.Fatalf("old token should be accepted before rotation: %v", err)
+	}
+
+	v.SetStaticTokens([]string{"new-token"})
+
+	if _, err := v.Verify(bearerRequest("old-token")); err == nil {
+		t.Fatal("old token should be rejected after rotation")
+	}
+	if _, err := v.Verify(bearerRequest("new-token")); err != nil {
+		t.Fatalf("new token should be accepted after rotation: %v", err)
+	}
+}
+
+func TestBearerVerifierExpiredJWT(t *testing.T) {
+	key := []byte("test-signing-key")
+	claims := jwt.MapClaims{
+		"sub": "user-1",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("signing test JWT: %v", err)
+	}
+
+	v := &auth.BearerVerifier{
+		Keyfunc: func(*jwt.Token) (interface{}, error) { return key, nil },
+	}
+
+	if _, err := v.Verify(bearerRequest(signed)); err == nil {
+		t.Fatal("expired JWT should be rejected")
+	}
+}
+
+func TestBearerVerifierValidJWTWithIssuerAndAudience(t *testing.T) {
+	key := []byte("test-signing-key")
+	claims := jwt.MapClaims{
+		"sub": "user-1",
+		"iss": "https://issuer.example.com",
+		"aud": "my-agent",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("signing test JWT: %v", err)
+	}
+
+	v := &auth.BearerVerifier{
+		Keyfunc:  func(*jwt.Token) (interface{}, error) { return key, nil },
+		Issuer:   "https://issuer.example.com",
+		Audience: "my-agent",
+	}
+
+	principal, err := v.Verify(bearerRequest(signed))
+	if err != nil {
+		t.Fatalf("valid JWT should be accepted: %v", err)
+	}
+	if principal.Subject != "user-1" {
+		t.Fatalf("got subject %q, want %q", principal.Subject, "user-1")
+	}
+
+	v.Audience = "other-agent"
+	if _, err := v.Verify(bearerRequest(signed)); err == nil {
+		t.Fatal("JWT with the wrong audience should be rejected")
+	}
+}
+
+func TestMiddlewareSchemeNegotiation(t *testing.T) {
+	verifiers := map[string]auth.Verifier{
+		"Bearer": auth.NewBearerVerifier([]string{"bearer-secret"}),
+		"Basic":  &auth.BasicVerifier{Validate: auth.StaticBasicValidator("alice", "hunter2")},
+	}
+
+	var reachedHandler bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reachedHandler = true
+		if _, ok := auth.PrincipalFromContext(r.Context()); !ok {
+			t.Error("handler should see an authenticated principal")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mw := auth.Middleware([]string{"Bearer", "Basic"}, verifiers, next)
+
+	// Only the second advertised scheme's credentials are presented;
+	// negotiation should still succeed.
+	reachedHandler = false
+	r := httptest.NewRequest(http.MethodPost, "/a2a", nil)
+	r.SetBasicAuth("alice", "hunter2")
+	w := httptest.NewRecorder()
+	mw.ServeHTTP(w, r)
+	if !reachedHandler || w.Code != http.StatusOK {
+		t.Fatalf("Basic credentials should satisfy negotiation, got status %d", w.Code)
+	}
+
+	// No credentials at all: neither scheme is satisfied.
+	reachedHandler = false
+	r = httptest.NewRequest(http.MethodPost, "/a2a", nil)
+	w = httptest.NewRecorder()
+	mw.ServeHTTP(w, r)
+	if reachedHandler {
+		t.Fatal("handler should not run without valid credentials")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestMiddlewareNoSchemesAllowsUnauthenticated(t *testing.T) {
+	var reachedHandler bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reachedHandler = true
+		w.WriteHeader(http.StatusOK)
+	})
+	mw := auth.Middleware(nil, nil, next)
+
+	w := httptest.NewRecorder()
+	mw.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/a2a", nil))
+	if !reachedHandler {
+		t.Fatal("a card with no advertised schemes should let requests through unauthenticated")
+	}
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+)
+
+// BasicVerifier implements the "Basic" scheme, delegating credential
+// validation to Validate.
+type BasicVerifier struct {
+	// Validate reports whether username/password are valid credentials.
+	// Use StaticBasicValidator for a fixed username/password pair.
+	Validate func(username, password string) bool
+}
+
+// Scheme returns "Basic".
+func (v *BasicVerifier) Scheme() string { return "Basic" }
+
+// Verify authenticates r's Authorization header against v.Validate.
+func (v *BasicVerifier) Verify(r *http.Request) (Principal, error) {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return Principal{}, fmt.Errorf("auth: missing Basic credentials")
+	}
+	if v.Validate == nil || !v.Validate(username, password) {
+		return Principal{}, fmt.Errorf("auth: invalid Basic credentials")
+	}
+	return Principal{Subject: username, Scheme: "Basic"}, nil
+}
+
+// StaticBasicValidator returns a Validate func that accepts exactly the
+// given username/password pair, compared in constant time.
+func StaticBasicValidator(username, password string) func(string, string) bool {
+	return func(u, p string) bool {
+		return subtle.ConstantTimeCompare([]byte(u), []byte(username)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(p), []byte(password)) == 1
+	}
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OIDCVerifier implements the "OIDC" scheme: it discovers the issuer's
+// token introspection endpoint from its discovery document, then calls
+// that endpoint for each bearer token, caching a positive result for
+// CacheTTL so repeated requests from the same caller don't re-introspect
+// on every call.
+type OIDCVerifier struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+
+	// CacheTTL bounds how long an introspection result is trusted.
+	// Defaults to one minute.
+	CacheTTL time.Duration
+
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	cache     map[string]cachedIntrospection
+}
+
+type oidcDiscovery struct {
+	IntrospectionEndpoint string `json:"introspection_endpoint"`
+}
+
+type cachedIntrospection struct {
+	principal Principal
+	expiresAt time.Time
+}
+
+// Scheme returns "OIDC".
+func (v *OIDCVerifier) Scheme() string { return "OIDC" }
+
+// Verify authenticates r's bearer token via token introspection,
+// consulting the cache first.
+func (v *OIDCVerifier) Verify(r *http.Request) (Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return Principal{}, fmt.Errorf("auth: missing OIDC bearer token")
+	}
+
+	if p, ok := v.cached(token); ok {
+		return p, nil
+	}
+
+	endpoint, err := v.introspectionEndpoint()
+	if err != nil {
+		return Principal{}, err
+	}
+
+	principal, err := v.introspect(endpoint, token)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	v.store(token, principal)
+	return principal, nil
+}
+
+func (v *OIDCVerifier) httpClient() *http.Client {
+	if v.HTTPClient != nil {
+		return v.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+func (v *OIDCVerifier) introspectionEndpoint() (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.discovery != nil {
+		return v.discovery.IntrospectionEndpoint, nil
+	}
+
+	resp, err := v.httpClient().Get(strings.TrimSuffix(v.IssuerURL, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return "", fmt.Errorf("auth: fetching OIDC discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var doc oidcDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", fmt.Errorf("auth: decoding OIDC discovery document: %w", err)
+	}
+	if doc.IntrospectionEndpoint == "" {
+		return "", fmt.Errorf("auth: OIDC discovery document has no introspection_endpoint")
+	}
+
+	v.discovery = &doc
+	return doc.IntrospectionEndpoint, nil
+}
+
+func (v *OIDCVerifier) introspect(endpoint, token string) (Principal, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(url.Values{"token": {token}}.Encode()))
+	if err != nil {
+		return Principal{}, fmt.Errorf("auth: building introspection request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(v.ClientID, v.ClientSecret)
+
+	resp, err := v.httpClient().Do(req)
+	if err != nil {
+		return Principal{}, fmt.Errorf("auth: calling introspection endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Active  bool   `json:"active"`
+		Subject string `json:"sub"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Principal{}, fmt.Errorf("auth: decoding introspection response: %w", err)
+	}
+	if !result.Active {
+		return Principal{}, fmt.Errorf("auth: token is not active")
+	}
+
+	return Principal{Subject: result.Subject, Scheme: "OIDC"}, nil
+}
+
+func (v *OIDCVerifier) cached(token string) (Principal, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	entry, ok := v.cache[token]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return Principal{}, false
+	}
+	return entry.principal, true
+}
+
+func (v *OIDCVerifier) store(token string, p Principal) {
+	ttl := v.CacheTTL
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.cache == nil {
+		v.cache = make(map[string]cachedIntrospection)
+	}
+	v.cache[token] = cachedIntrospection{principal: p, expiresAt: time.Now().Add(ttl)}
+}
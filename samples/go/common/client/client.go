@@ -1,48 +1,192 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/google/A2A/samples/go/schema"
 )
 
 // Client provides methods for interacting with A2A agents
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL     string
+	httpClient  *http.Client
+	timeout     time.Duration
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+// ClientOption configures optional behavior on a Client built by
+// NewClient. Without any options a Client makes a single attempt per
+// call with no deadline beyond ctx or the underlying http.Client's own
+// timeout — today's behavior.
+type ClientOption func(*Client)
+
+// WithHTTPClient overrides the *http.Client a Client issues requests
+// with, e.g. to share connection pooling or add a transport-level proxy.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) { c.httpClient = hc }
 }
 
-// NewClient creates a new A2A client
-func NewClient(baseURL string) *Client {
-	return &Client{
-		baseURL:    baseURL,
-		httpClient: &http.Client{},
+// WithTimeout bounds every single-response JSON-RPC call (e.g. GetTask,
+// SetTaskPushNotification) and agent-card fetch to d. It deliberately
+// does not apply to SendSubscribe, Subscribe, or resubscribe: an HTTP
+// request's context bounds the whole response body's lifetime, not just
+// establishing the connection, so timing those out the same way would
+// cut an open SSE stream off after d instead of letting it run until the
+// server ends it or the caller's ctx is canceled.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) { c.timeout = d }
+}
+
+// WithRetry configures the backoff policy shared by retried JSON-RPC
+// calls (see callIdempotent) and Subscribe's automatic SSE reconnect: up
+// to maxAttempts total tries, starting at baseDelay and doubling up to
+// maxDelay between them, with jitter — mirroring
+// pushnotify.Dispatcher's delivery retry policy. maxAttempts of 1 (the
+// default) disables retry and reconnect entirely.
+func WithRetry(maxAttempts int, baseDelay, maxDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxAttempts = maxAttempts
+		c.baseDelay = baseDelay
+		c.maxDelay = maxDelay
 	}
 }
 
-// GetAgentCard retrieves the agent's metadata and capabilities
-func (c *Client) GetAgentCard() (*AgentCard, error) {
-	resp, err := c.httpClient.Get(c.baseURL + "/agent-card")
-	if err != nil {
-		return nil, err
+// NewClient creates a new A2A client.
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:     baseURL,
+		httpClient:  &http.Client{},
+		maxAttempts: 1,
 	}
-	defer resp.Body.Close()
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// withTimeout returns ctx bounded by c.timeout, and the cancel func the
+// caller must defer; if no timeout is configured it returns ctx as-is.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// withRetry invokes fn until it succeeds, retryable returns false for
+// its error, or c's attempt budget is exhausted, sleeping the backoff
+// nextBackoff computes between attempts.
+func (c *Client) withRetry(ctx context.Context, retryable func(error) bool, fn func() error) error {
+	maxAttempts := c.maxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	delay := c.baseDelay
+
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		lastErr = fn()
+		if lastErr == nil || !retryable(lastErr) || attempt == maxAttempts {
+			return lastErr
+		}
 
+		var wait time.Duration
+		wait, delay = c.nextBackoff(delay)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return lastErr
+}
+
+// nextBackoff returns the jittered duration to wait before the next
+// retry attempt, and the base delay to pass back in on the attempt after
+// that (doubled, capped to c.maxDelay if one is configured).
+func (c *Client) nextBackoff(delay time.Duration) (wait, next time.Duration) {
+	jitter := time.Duration(rand.Int63n(int64(delay) + 1))
+	wait = delay + jitter
+	next = delay * 2
+	if c.maxDelay > 0 && next > c.maxDelay {
+		next = c.maxDelay
+	}
+	return wait, next
+}
+
+// isTransportError reports whether err represents a failure to complete
+// the HTTP round trip or decode its body, as opposed to a well-formed
+// JSON-RPC error response, which retrying verbatim won't fix.
+func isTransportError(err error) bool {
+	var rpc *rpcErr
+	return !errors.As(err, &rpc)
+}
+
+// GetAgentCard retrieves the agent's metadata and capabilities, retrying
+// transport failures per c's retry policy. Like call, each attempt gets
+// its own fresh c.withTimeout deadline rather than one deadline shared
+// across every retry, so WithTimeout bounds a single attempt the same
+// way for GetAgentCard as it does for call-based methods.
+func (c *Client) GetAgentCard() (*AgentCard, error) {
 	var card AgentCard
-	if err := json.NewDecoder(resp.Body).Decode(&card); err != nil {
+	err := c.withRetry(context.Background(), isTransportError, func() error {
+		ctx, cancel := c.withTimeout(context.Background())
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/agent-card", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		card = AgentCard{}
+		return json.NewDecoder(resp.Body).Decode(&card)
+	})
+	if err != nil {
 		return nil, err
 	}
-
 	return &card, nil
 }
 
-// SendMessage sends a message to the agent and returns a response reader
+// SendMessage sends a message to the agent and returns a response reader.
+// It's SendSubscribe with a background context; callers that need
+// cancellation should use SendSubscribe or Subscribe directly.
 func (c *Client) SendMessage(taskID string, message Message) (*TaskResponseReader, error) {
+	return c.SendSubscribe(context.Background(), taskID, message)
+}
+
+// SendSubscribe issues a tasks/sendSubscribe call and returns a reader
+// over its SSE response stream. The request is bound to ctx, so an
+// in-flight read can be interrupted by canceling it.
+//
+// The JSON-RPC request ID is its own freshly generated UUID, distinct
+// from taskID: a client may send several messages against the same task
+// (e.g. a follow-up after input-required), and reusing taskID as the
+// request ID would make those calls indistinguishable to anything
+// correlating requests and responses by ID.
+func (c *Client) SendSubscribe(ctx context.Context, taskID string, message Message) (*TaskResponseReader, error) {
 	req := JSONRPCRequest{
 		JSONRPC: "2.0",
 		Method:  "tasks/sendSubscribe",
-		ID:      taskID,
+		ID:      uuid.New().String(),
 		Params: map[string]interface{}{
 			"id":      taskID,
 			"message": message,
@@ -54,46 +198,199 @@ func (c *Client) SendMessage(taskID string, message Message) (*TaskResponseReade
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Post(c.baseURL, "application/json", bytes.NewBuffer(reqBody))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, err
 	}
+	httpReq.Header.Set("Content-Type", "application/json")
 
-	return &TaskResponseReader{response: resp}, nil
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTaskResponseReader(resp), nil
 }
 
-// TaskResponseReader handles reading SSE responses from the agent
-type TaskResponseReader struct {
-	response *http.Response
+// Subscribe is SendSubscribe reshaped as a channel-based stream: it
+// starts the call on its own goroutine and delivers each TaskResponse on
+// the returned channel as it arrives. Both channels close once the
+// stream ends, ctx is canceled, or reconnection is exhausted; a non-nil
+// error is sent on the error channel first unless the stream simply
+// reached its end or ctx ended the wait.
+//
+// On a transport-level read error (the connection drops mid-stream, as
+// opposed to the server ending the stream cleanly), Subscribe
+// automatically reattaches via tasks/resubscribe, retrying with the
+// backoff WithRetry configures. This is a best-effort reconnect, not a
+// gapless resume: the server's tasks/resubscribe handler re-subscribes
+// to the task's live event stream from whenever the new connection
+// attaches, with no replay of events emitted during the gap — the
+// server keeps no per-event log to replay from, and its SSE stream
+// carries no event IDs to resume by (see agent/streaming.go's
+// handleTaskResubscribe and streamSSE). Callers that can't tolerate a
+// missed update should poll GetTask after a reconnect to recheck the
+// task's current state. WithRetry's default maxAttempts of 1 disables
+// reconnection entirely, matching pre-existing behavior.
+func (c *Client) Subscribe(ctx context.Context, taskID string, message Message) (<-chan *TaskResponse, <-chan error) {
+	events := make(chan *TaskResponse)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		reader, err := c.SendSubscribe(ctx, taskID, message)
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		maxAttempts := c.maxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = 1
+		}
+		attempt := 1
+		delay := c.baseDelay
+
+		for {
+			resp, err := reader.Read()
+			if err != nil {
+				reader.Close()
+				if err == io.EOF {
+					return
+				}
+
+				if !isStreamTransportError(err) || attempt >= maxAttempts {
+					select {
+					case errs <- err:
+					case <-ctx.Done():
+					}
+					return
+				}
+				attempt++
+
+				var wait time.Duration
+				wait, delay = c.nextBackoff(delay)
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return
+				}
+
+				reader, err = c.resubscribe(ctx, taskID)
+				if err != nil {
+					select {
+					case errs <- err:
+					case <-ctx.Done():
+					}
+					return
+				}
+				continue
+			}
+
+			attempt = 1
+			delay = c.baseDelay
+			select {
+			case events <- resp:
+			case <-ctx.Done():
+				reader.Close()
+				return
+			}
+		}
+	}()
+
+	return events, errs
 }
 
-// Read returns the next response from the agent, or error if the stream is closed
-func (r *TaskResponseReader) Read() (*TaskResponse, error) {
-	if r.response == nil {
-		return nil, fmt.Errorf("response stream closed")
+// resubscribe issues a tasks/resubscribe call for taskID and returns a
+// reader over its SSE response stream, the same shape SendSubscribe
+// returns. Used by Subscribe to reattach after a dropped connection.
+func (c *Client) resubscribe(ctx context.Context, taskID string) (*TaskResponseReader, error) {
+	req := JSONRPCRequest{
+		JSONRPC: "2.0",
+		Method:  "tasks/resubscribe",
+		ID:      uuid.New().String(),
+		Params:  TaskIdParams{ID: taskID},
 	}
 
-	// Read the SSE data line
-	buf := make([]byte, 1024)
-	n, err := r.response.Body.Read(buf)
+	reqBody, err := json.Marshal(req)
 	if err != nil {
-		r.response.Body.Close()
-		r.response = nil
 		return nil, err
 	}
 
-	data := string(buf[:n])
-	if !isSSEData(data) {
-		return nil, nil // Not a data line, skip
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
 	}
+	httpReq.Header.Set("Content-Type", "application/json")
 
-	// Parse the JSON response
-	var resp TaskResponse
-	if err := json.Unmarshal([]byte(getSSEData(data)), &resp); err != nil {
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
 		return nil, err
 	}
 
-	return &resp, nil
+	return newTaskResponseReader(resp), nil
+}
+
+// TaskResponseReader decodes the server-sent events stream returned by
+// tasks/sendSubscribe into one TaskResponse per event.
+type TaskResponseReader struct {
+	response *http.Response
+	scanner  *bufio.Scanner
+}
+
+// maxSSEEventSize caps a single SSE event well above any realistic task
+// status/message payload, while still bounding how much of a malformed,
+// never-terminated stream the scanner will buffer before giving up.
+const maxSSEEventSize = 10 * 1024 * 1024
+
+func newTaskResponseReader(resp *http.Response) *TaskResponseReader {
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSSEEventSize)
+	scanner.Split(splitSSEEvent)
+	return &TaskResponseReader{response: resp, scanner: scanner}
+}
+
+// Read returns the next TaskResponse from the stream. It returns io.EOF
+// once the stream ends cleanly, and closes the underlying response body
+// on any terminal return (success or error) so callers don't also need
+// to call Close in the common case of reading until EOF.
+//
+// Each SSE event is accumulated line-by-line until the blank line that
+// terminates it, per the SSE spec, regardless of how many data:/event:
+// lines it spans or where a single Body.Read happened to end. Comment
+// lines (starting with ":") are ignored, multi-line "data:" fields are
+// joined with "\n", and event/id/retry fields are parsed but unused by
+// this client, which only cares about the data payload.
+func (r *TaskResponseReader) Read() (*TaskResponse, error) {
+	if r.response == nil {
+		return nil, fmt.Errorf("response stream closed")
+	}
+
+	for r.scanner.Scan() {
+		ev := parseSSEEvent(r.scanner.Bytes())
+		if ev.data == "" {
+			continue
+		}
+
+		var resp TaskResponse
+		if err := json.Unmarshal([]byte(ev.data), &resp); err != nil {
+			r.Close()
+			return nil, &streamDecodeError{fmt.Errorf("decode SSE event: %w", err)}
+		}
+		return &resp, nil
+	}
+
+	err := r.scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		err = &streamDecodeError{err}
+	}
+	if err == nil {
+		err = io.EOF
+	}
+	r.Close()
+	return nil, err
 }
 
 // Close closes the response stream
@@ -106,6 +403,175 @@ func (r *TaskResponseReader) Close() error {
 	return nil
 }
 
+// streamDecodeError marks a Read failure to parse an SSE event's payload
+// (malformed JSON, or an event past maxSSEEventSize) as opposed to a
+// transport-level failure to read the stream at all. Subscribe uses this
+// distinction to decide whether reconnecting could plausibly help: a
+// decode failure is deterministic and would recur on the resent event,
+// so reconnecting would only burn retry budget without fixing anything.
+type streamDecodeError struct{ err error }
+
+func (e *streamDecodeError) Error() string { return e.err.Error() }
+func (e *streamDecodeError) Unwrap() error { return e.err }
+
+// isStreamTransportError reports whether err from TaskResponseReader.Read
+// represents a dropped connection or similar transport failure, as
+// opposed to a streamDecodeError.
+func isStreamTransportError(err error) bool {
+	var decodeErr *streamDecodeError
+	return !errors.As(err, &decodeErr)
+}
+
+// rpcErr mirrors the JSON-RPC 2.0 error object.
+type rpcErr struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *rpcErr) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      string          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcErr         `json:"error,omitempty"`
+}
+
+// call issues a single-response JSON-RPC request — as opposed to
+// SendSubscribe's SSE stream — and decodes its result into result, which
+// may be nil to discard it. It makes one attempt, bounded by c's
+// configured timeout if any; see callIdempotent for retrying callers.
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	req := JSONRPCRequest{
+		JSONRPC: "2.0",
+		Method:  method,
+		ID:      uuid.New().String(),
+		Params:  params,
+	}
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var rpcResp rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("decode %q response: %w", method, err)
+	}
+	if rpcResp.Error != nil {
+		return rpcResp.Error
+	}
+	if result != nil && len(rpcResp.Result) > 0 {
+		if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+			return fmt.Errorf("decode %q result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// callIdempotent is call wrapped in c's retry policy. Only use it for
+// methods safe to invoke more than once for the same effect — queries
+// like tasks/get and tasks/pushNotification/get — since a request that
+// reached the server but whose response was lost to a transport error
+// will be resent.
+func (c *Client) callIdempotent(ctx context.Context, method string, params interface{}, result interface{}) error {
+	return c.withRetry(ctx, isTransportError, func() error {
+		return c.call(ctx, method, params, result)
+	})
+}
+
+// SetTaskPushNotification registers config as the webhook taskID's agent
+// should call as the task's status changes, via tasks/pushNotification/set.
+func (c *Client) SetTaskPushNotification(taskID string, config PushNotificationConfig) error {
+	return c.call(context.Background(), "tasks/pushNotification/set", taskPushNotificationConfig{
+		ID:                     taskID,
+		PushNotificationConfig: config,
+	}, nil)
+}
+
+// GetTaskPushNotification fetches taskID's currently registered webhook
+// config via tasks/pushNotification/get, retrying transport failures per
+// c's retry policy. It returns an error if the task has no config
+// registered.
+func (c *Client) GetTaskPushNotification(taskID string) (*PushNotificationConfig, error) {
+	var result taskPushNotificationConfig
+	if err := c.callIdempotent(context.Background(), "tasks/pushNotification/get", TaskIdParams{ID: taskID}, &result); err != nil {
+		return nil, err
+	}
+	return &result.PushNotificationConfig, nil
+}
+
+// GetTask fetches taskID's current state via tasks/get, retrying
+// transport failures per c's retry policy since a lookup has no side
+// effect to duplicate. historyLength, if positive, requests that many of
+// the task's most recent messages back in Task.History.
+func (c *Client) GetTask(taskID string, historyLength int) (*Task, error) {
+	params := map[string]interface{}{"id": taskID}
+	if historyLength > 0 {
+		params["historyLength"] = historyLength
+	}
+
+	var task Task
+	if err := c.callIdempotent(context.Background(), "tasks/get", params, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Task is the full state tasks/get returns for a task — its status plus
+// whatever artifacts and history have accumulated — as opposed to
+// TaskResponse's per-SSE-event shape. It's schema.Task directly, like
+// Message/Part/Artifact below, rather than a hand-copy that would drift
+// out of sync with fields such as Status.Timestamp or Metadata.
+type Task = schema.Task
+
+// PushNotificationConfig tells an agent where, and how, to deliver
+// out-of-band task status updates.
+type PushNotificationConfig struct {
+	URL            string          `json:"url"`
+	Token          *string         `json:"token,omitempty"`
+	Authentication *Authentication `json:"authentication,omitempty"`
+}
+
+// Authentication names the schemes an agent should use to authenticate
+// itself to a push notification callback, and any shared credential
+// those schemes need (e.g. a basic-auth value or an HMAC signing key).
+type Authentication struct {
+	Schemes     []string `json:"schemes"`
+	Credentials *string  `json:"credentials,omitempty"`
+}
+
+// taskPushNotificationConfig is the wire shape of both
+// tasks/pushNotification/set's params and result.
+type taskPushNotificationConfig struct {
+	ID                     string                 `json:"id"`
+	PushNotificationConfig PushNotificationConfig `json:"pushNotificationConfig"`
+}
+
+// TaskIdParams are parameters for operations needing only a task ID, such
+// as tasks/pushNotification/get.
+type TaskIdParams struct {
+	ID string `json:"id"`
+}
+
 // Helper types for JSON-RPC requests/responses
 type JSONRPCRequest struct {
 	JSONRPC string      `json:"jsonrpc"`
@@ -114,21 +580,28 @@ type JSONRPCRequest struct {
 	Params  interface{} `json:"params"`
 }
 
-type Message struct {
-	Role  string     `json:"role"`
-	Parts []TextPart `json:"parts"`
-}
-
-type TextPart struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
-}
+// Message, Part, and Artifact reuse schema's discriminated-union wire
+// types directly rather than keeping a second hand-maintained copy of
+// the same TextPart/FilePart/DataPart variants and their custom
+// marshaling in sync by hand — schema.Part's variants are sealed to
+// schema's own types (partType is unexported), so there would be no way
+// to implement the union from this package even if we wanted to.
+type Message = schema.Message
+type Part = schema.Part
+type TextPart = schema.TextPart
+type FilePart = schema.FilePart
+type DataPart = schema.DataPart
+type FileContent = schema.FileContent
+type FileContentBytes = schema.FileContentBytes
+type FileContentUri = schema.FileContentUri
+type Artifact = schema.Artifact
 
 type TaskResponse struct {
 	Result struct {
-		ID     string     `json:"id"`
-		Status TaskStatus `json:"status"`
-		Final  bool       `json:"final"`
+		ID       string     `json:"id"`
+		Status   TaskStatus `json:"status"`
+		Artifact *Artifact  `json:"artifact,omitempty"`
+		Final    bool       `json:"final"`
 	} `json:"result"`
 }
 
@@ -138,13 +611,13 @@ type TaskStatus struct {
 }
 
 type AgentCard struct {
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Version     string   `json:"version"`
-	Provider    Provider `json:"provider"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Version      string   `json:"version"`
+	Provider     Provider `json:"provider"`
 	Capabilities struct {
-		Streaming             bool `json:"streaming"`
-		PushNotifications     bool `json:"pushNotifications"`
+		Streaming              bool `json:"streaming"`
+		PushNotifications      bool `json:"pushNotifications"`
 		StateTransitionHistory bool `json:"stateTransitionHistory"`
 	} `json:"capabilities"`
 	Authentication     interface{} `json:"authentication"`
@@ -156,11 +629,55 @@ type Provider struct {
 	Organization string `json:"organization"`
 }
 
-// Helper functions for SSE parsing
-func isSSEData(line string) bool {
-	return len(line) > 5 && line[:5] == "data:"
+// sseEvent is one parsed server-sent event block.
+type sseEvent struct {
+	event string
+	data  string
+	id    string
+	retry string
 }
 
-func getSSEData(line string) string {
-	return line[5:] // Strip "data:" prefix
-}
\ No newline at end of file
+// splitSSEEvent is a bufio.SplitFunc that frames an SSE byte stream on
+// the blank line terminating each event (a "\n\n" or "\r\n\r\n"), per
+// the SSE spec, rather than assuming an event arrives in a single Read.
+// At EOF, any trailing unterminated data is returned as a final token.
+func splitSSEEvent(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.Index(data, []byte("\n\n")); i >= 0 {
+		return i + 2, data[:i], nil
+	}
+	if i := bytes.Index(data, []byte("\r\n\r\n")); i >= 0 {
+		return i + 4, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// parseSSEEvent parses a single event block (as framed by splitSSEEvent)
+// into its fields. Comment lines beginning with ":" are ignored, and
+// multiple "data:" lines are joined with "\n" as the spec requires.
+func parseSSEEvent(block []byte) sseEvent {
+	var ev sseEvent
+	var dataLines []string
+	for _, line := range strings.Split(string(block), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" || strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.event = value
+		case "data":
+			dataLines = append(dataLines, value)
+		case "id":
+			ev.id = value
+		case "retry":
+			ev.retry = value
+		}
+	}
+	ev.data = strings.Join(dataLines, "\n")
+	return ev
+}
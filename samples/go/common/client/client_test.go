@@ -0,0 +1,238 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSSEEventJoinsMultiLineData(t *testing.T) {
+	block := []byte("event: message\ndata: line one\ndata: line two\nid: 1\n")
+	ev := parseSSEEvent(block)
+	if ev.event != "message" {
+		t.Errorf("event = %q, want %q", ev.event, "message")
+	}
+	if want := "line one\nline two"; ev.data != want {
+		t.Errorf("data = %q, want %q", ev.data, want)
+	}
+	if ev.id != "1" {
+		t.Errorf("id = %q, want %q", ev.id, "1")
+	}
+}
+
+func TestParseSSEEventIgnoresCommentsAndBlankLines(t *testing.T) {
+	block := []byte(": heartbeat\n\ndata: hello\n: another comment\n")
+	ev := parseSSEEvent(block)
+	if ev.data != "hello" {
+		t.Errorf("data = %q, want %q (comment/blank lines must be ignored)", ev.data, "hello")
+	}
+}
+
+func TestSplitSSEEventFramesOnBlankLine(t *testing.T) {
+	cases := []struct {
+		name string
+		sep  string
+	}{
+		{"LF", "\n\n"},
+		{"CRLF", "\r\n\r\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := "data: first" + tc.sep + "data: second" + tc.sep
+			advance, token, err := splitSSEEvent([]byte(input), false)
+			if err != nil {
+				t.Fatalf("splitSSEEvent: %v", err)
+			}
+			if string(token) != "data: first" {
+				t.Errorf("token = %q, want %q", token, "data: first")
+			}
+			if advance != len("data: first"+tc.sep) {
+				t.Errorf("advance = %d, want %d", advance, len("data: first"+tc.sep))
+			}
+		})
+	}
+}
+
+func TestSplitSSEEventWaitsForMoreDataMidEvent(t *testing.T) {
+	advance, token, err := splitSSEEvent([]byte("data: partial"), false)
+	if err != nil || token != nil || advance != 0 {
+		t.Fatalf("splitSSEEvent on incomplete, non-EOF data = (%d, %q, %v), want (0, nil, nil)", advance, token, err)
+	}
+}
+
+func TestSplitSSEEventReturnsTrailingUnterminatedEventAtEOF(t *testing.T) {
+	advance, token, err := splitSSEEvent([]byte("data: never terminated"), true)
+	if err != nil {
+		t.Fatalf("splitSSEEvent: %v", err)
+	}
+	if string(token) != "data: never terminated" {
+		t.Errorf("token = %q, want the trailing data returned as a final token", token)
+	}
+	if advance != len("data: never terminated") {
+		t.Errorf("advance = %d, want %d", advance, len("data: never terminated"))
+	}
+}
+
+// newTestReader lets a test drive TaskResponseReader.Read against an
+// in-memory stream without a real network round trip.
+func newTestReader(body string) *TaskResponseReader {
+	resp := &http.Response{Body: io.NopCloser(strings.NewReader(body))}
+	return newTaskResponseReader(resp)
+}
+
+func TestReadDecodesMultiLineEvent(t *testing.T) {
+	r := newTestReader("data: {\"result\":{\"id\":\"t1\",\n" +
+		"data: \"status\":{\"state\":\"working\"},\"final\":false}}\n\n")
+	resp, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if resp.Result.ID != "t1" || resp.Result.Status.State != "working" {
+		t.Errorf("got %+v", resp.Result)
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("second Read err = %v, want io.EOF", err)
+	}
+}
+
+func TestReadSkipsHeartbeatsBetweenEvents(t *testing.T) {
+	body := ": heartbeat\n\ndata: {\"result\":{\"id\":\"t1\",\"status\":{\"state\":\"completed\"},\"final\":true}}\n\n"
+	r := newTestReader(body)
+	resp, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if resp.Result.ID != "t1" {
+		t.Errorf("got %+v, want id t1 (heartbeat-only event must be skipped, not returned or erred on)", resp.Result)
+	}
+}
+
+func TestReadMaxSSEEventSizeOverflow(t *testing.T) {
+	oversized := "data: " + strings.Repeat("x", maxSSEEventSize+1)
+	r := newTestReader(oversized)
+	_, err := r.Read()
+	if err == nil {
+		t.Fatal("expected an error for an event past maxSSEEventSize")
+	}
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("err = %v, want it to wrap bufio.ErrTooLong", err)
+	}
+	if isStreamTransportError(err) {
+		t.Error("an oversized event should be a streamDecodeError, not a transport error: resending the same event would overflow again")
+	}
+}
+
+func TestReadMalformedJSONIsDecodeErrorNotTransportError(t *testing.T) {
+	r := newTestReader("data: not json\n\n")
+	_, err := r.Read()
+	if err == nil {
+		t.Fatal("expected an error for malformed JSON")
+	}
+	if isStreamTransportError(err) {
+		t.Error("malformed JSON should be classified as a streamDecodeError, not a transport error")
+	}
+}
+
+// sseHijackServer, on its first connection only, flushes the response
+// headers and then hijacks and closes the raw TCP connection before
+// writing any event — the same shape as a dropped connection — so the
+// second connection (reconnected via tasks/resubscribe) serves every
+// event in events cleanly.
+func sseHijackServer(t *testing.T, events []string) (*http.Server, net.Listener) {
+	t.Helper()
+	var calls int
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "text/event-stream")
+		if calls == 1 {
+			w.(http.Flusher).Flush()
+			hj, ok := w.(http.Hijacker)
+			if !ok {
+				t.Fatal("ResponseWriter does not support hijacking")
+			}
+			conn, _, err := hj.Hijack()
+			if err != nil {
+				t.Fatal(err)
+			}
+			conn.Close()
+			return
+		}
+		for _, ev := range events {
+			io.WriteString(w, ev)
+			w.(http.Flusher).Flush()
+		}
+	})}
+	go srv.Serve(ln)
+	return srv, ln
+}
+
+func TestSubscribeReconnectsAfterTransportError(t *testing.T) {
+	event := "data: {\"result\":{\"id\":\"t1\",\"status\":{\"state\":\"completed\"},\"final\":true}}\n\n"
+	srv, ln := sseHijackServer(t, []string{event})
+	defer srv.Close()
+
+	c := NewClient("http://"+ln.Addr().String(), WithRetry(3, time.Millisecond, 5*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, errs := c.Subscribe(ctx, "t1", Message{Role: "user"})
+	select {
+	case resp, ok := <-events:
+		if !ok {
+			t.Fatal("events channel closed before delivering the post-reconnect event")
+		}
+		if resp.Result.ID != "t1" || resp.Result.Status.State != "completed" {
+			t.Errorf("got %+v", resp.Result)
+		}
+	case err := <-errs:
+		t.Fatalf("unexpected error instead of a reconnected event: %v", err)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the reconnected event")
+	}
+}
+
+func TestSubscribeDoesNotReconnectOnDecodeError(t *testing.T) {
+	var calls int
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: not json\n\n")
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	c := NewClient("http://"+ln.Addr().String(), WithRetry(3, time.Millisecond, 5*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	events, errs := c.Subscribe(ctx, "t1", Message{Role: "user"})
+	select {
+	case resp := <-events:
+		t.Fatalf("unexpected event %+v, want a decode error", resp)
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("expected a non-nil decode error")
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the decode error")
+	}
+
+	time.Sleep(20 * time.Millisecond) // let any stray reconnect attempt land
+	if calls != 1 {
+		t.Errorf("server received %d requests, want 1 (a decode error must not trigger a tasks/resubscribe reconnect)", calls)
+	}
+}
@@ -8,12 +8,17 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/google/A2A/samples/go/common/client"
+	"github.com/google/A2A/samples/go/pushreceiver"
+	"github.com/google/A2A/samples/go/schema"
 )
 
 // ANSI color codes
@@ -201,6 +206,60 @@ func getStatusEmoji(state string) string {
 	}
 }
 
+// registerPushNotification tells the agent to POST task status updates
+// for taskID to callbackURL, authenticated with a bearer token if one is
+// set, via client.Client.SetTaskPushNotification.
+func registerPushNotification(pushClient *client.Client, taskID, callbackURL, token string) error {
+	var tokenPtr *string
+	if token != "" {
+		tokenPtr = &token
+	}
+	return pushClient.SetTaskPushNotification(taskID, client.PushNotificationConfig{URL: callbackURL, Token: tokenPtr})
+}
+
+// startPushListener serves pushreceiver's handler on addr in the
+// background, printing each task update it receives the same way
+// handleStreamingResponse prints a completed SSE stream's last event.
+// It binds addr synchronously so a failure (e.g. the port is already in
+// use) is reported before the caller registers the callback with the
+// agent, rather than racing a registration that's doomed to go nowhere.
+func startPushListener(addr, token string, card *AgentCard) error {
+	handler := pushreceiver.NewHandler(pushreceiver.Config{Token: token}, func(task *schema.Task) {
+		printPushUpdate(card, task)
+	})
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := http.Serve(ln, handler); err != nil {
+			fmt.Printf("%s❌ Push notification listener failed: %v%s\n", colorRed, err, colorReset)
+		}
+	}()
+	fmt.Printf("%s✓ Listening for push notifications on %s%s\n", colorGreen, addr, colorReset)
+	return nil
+}
+
+// printPushUpdate renders a task update delivered out-of-band, as
+// opposed to handleStreamingResponse's in-band SSE rendering.
+func printPushUpdate(card *AgentCard, task *schema.Task) {
+	timestamp := time.Now().Format("15:04:05")
+	fmt.Printf("\n%s%s [%s] (push):%s", colorPurple, card.Name, timestamp, colorReset)
+
+	state := string(task.Status.State)
+	emoji := getStatusEmoji(state)
+	stateColor := getStateColor(state)
+	fmt.Printf(" %s %s%s%s\n", emoji, stateColor, state, colorReset)
+
+	if task.Status.Message != nil {
+		for _, part := range task.Status.Message.Parts {
+			if tp, ok := part.(schema.TextPart); ok {
+				fmt.Printf("  %s%s%s\n", colorCyan, tp.Text, colorReset)
+			}
+		}
+	}
+}
+
 func getStateColor(state string) string {
 	switch state {
 	case "working":
@@ -218,6 +277,9 @@ func getStateColor(state string) string {
 
 func main() {
 	agentURL := flag.String("agent", "http://localhost:10000", "URL of the A2A agent")
+	listenAddr := flag.String("listen", "", "address to listen on for out-of-band push notifications (e.g. :9000); requires --push-url")
+	pushURL := flag.String("push-url", "", "externally reachable URL for --listen, registered with the agent as its push notification callback")
+	pushToken := flag.String("push-token", "", "bearer token the agent must send with push notifications, and that --listen requires of callbacks")
 	flag.Parse()
 
 	// Fetch agent card
@@ -234,10 +296,32 @@ func main() {
 		fmt.Printf("  Version:     %s\n", card.Version)
 	}
 
+	var pushClient *client.Client
+	if *listenAddr != "" {
+		if *pushToken == "" {
+			fmt.Print(colorize(colorYellow, "⚠️ --listen is set without --push-token: anyone who can reach this address can forge a push notification.\n"))
+		}
+		if *pushURL == "" {
+			fmt.Print(colorize(colorYellow, "⚠️ --listen is set without --push-url, so the agent is never told to call it; no push notifications will arrive.\n"))
+		} else {
+			pushClient = client.NewClient(*agentURL)
+		}
+		if err := startPushListener(*listenAddr, *pushToken, card); err != nil {
+			fmt.Printf("%s❌ Error starting push notification listener: %v%s\n", colorRed, err, colorReset)
+			pushClient = nil
+		}
+	}
+
 	taskID := uuid.New().String()
 	fmt.Printf("%sStarting Task ID: %s%s\n", colorGray, taskID, colorReset)
 	fmt.Printf("%sEnter messages, or use '/new' to start a new task.%s\n", colorGray, colorReset)
 
+	if pushClient != nil {
+		if err := registerPushNotification(pushClient, taskID, *pushURL, *pushToken); err != nil {
+			fmt.Printf("%s❌ Error registering push notification: %v%s\n", colorRed, err, colorReset)
+		}
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Printf("%s%s > You:%s ", colorCyan, card.Name, colorReset)
@@ -258,6 +342,11 @@ func main() {
 		if input == "/new" {
 			taskID = uuid.New().String()
 			fmt.Printf("%s✨ Starting new Task ID: %s%s\n", "\033[1m", taskID, colorReset)
+			if pushClient != nil {
+				if err := registerPushNotification(pushClient, taskID, *pushURL, *pushToken); err != nil {
+					fmt.Printf("%s❌ Error registering push notification: %v%s\n", colorRed, err, colorReset)
+				}
+			}
 			continue
 		}
 
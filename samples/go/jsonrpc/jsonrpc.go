@@ -0,0 +1,292 @@
+// Package jsonrpc implements a bidirectional JSON-RPC 2.0 connection: a
+// Conn can issue outbound Calls and Notifications to its peer while
+// concurrently dispatching inbound requests to a Handler, over any
+// transport that can frame discrete messages. A plain HTTP POST handler
+// only gets one message each way per round trip, so it can't use a Conn
+// for the full duration of a conversation — but a WebSocket or stdio
+// transport can, which is what lets an agent call back into its client
+// mid-task instead of only ever replying to it.
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// CancelMethod is the notification method a Conn sends to ask its peer to
+// abandon an in-flight request it issued. It plays the same role at the
+// transport level that A2A's tasks/cancel plays at the task level.
+const CancelMethod = "$/cancelRequest"
+
+// cancelParams is CancelMethod's notification payload.
+type cancelParams struct {
+	ID *interface{} `json:"id"`
+}
+
+// Handler processes one inbound request or notification and returns its
+// result. ctx is canceled if the peer sends a CancelMethod notification
+// naming this request's ID before Handler returns; Handler is responsible
+// for noticing ctx.Done() the same way TaskHandler and TaskStreamHandler
+// implementations already do. req.ID is nil for a notification, and
+// whatever Handler returns for one is discarded rather than sent back.
+type Handler func(ctx context.Context, conn *Conn, req *schema.JSONRPCRequest) (result interface{}, rpcErr *schema.JSONRPCError)
+
+// wireMessage is the on-the-wire shape of either a request, a
+// notification, or a response: Method set means the former two, unset
+// means the latter. Conn never exposes this type directly.
+type wireMessage struct {
+	JSONRPC string               `json:"jsonrpc"`
+	ID      *interface{}         `json:"id,omitempty"`
+	Method  string               `json:"method,omitempty"`
+	Params  json.RawMessage      `json:"params,omitempty"`
+	Result  json.RawMessage      `json:"result,omitempty"`
+	Error   *schema.JSONRPCError `json:"error,omitempty"`
+}
+
+// Conn is one peer's end of a bidirectional JSON-RPC connection. The zero
+// Conn is not usable; construct one with NewConn.
+type Conn struct {
+	stream Stream
+
+	seq atomic.Int64
+
+	mu       sync.Mutex
+	pending  map[string]chan wireMessage   // outbound calls awaiting a response, by request ID
+	handling map[string]context.CancelFunc // inbound requests currently running, by request ID
+}
+
+// NewConn wraps stream in a Conn. Call Run to start servicing it.
+func NewConn(stream Stream) *Conn {
+	return &Conn{
+		stream:   stream,
+		pending:  make(map[string]chan wireMessage),
+		handling: make(map[string]context.CancelFunc),
+	}
+}
+
+// Run reads messages from c's stream until the stream is closed or
+// returns a read error, dispatching inbound requests and notifications to
+// handler (which may be nil if this peer never expects any) and routing
+// responses to whichever Call is waiting on them. handler runs each
+// inbound request in its own goroutine so a slow one doesn't stop Run
+// from noticing a subsequent CancelMethod notification for it. Run blocks
+// until the connection ends; a graceful close surfaces as a nil error.
+func (c *Conn) Run(ctx context.Context, handler Handler) error {
+	for {
+		msg, err := c.stream.ReadMessage()
+		if err != nil {
+			c.failPending(err)
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		switch {
+		case msg.Method == CancelMethod:
+			c.handleCancel(msg)
+		case msg.Method != "":
+			c.dispatch(ctx, handler, msg)
+		default:
+			c.resolve(msg)
+		}
+	}
+}
+
+// Call issues method with params to the peer and waits for its response,
+// decoding its result into result (which may be nil to discard it) on
+// success. If ctx is canceled before the peer responds, Call sends a
+// CancelMethod notification naming this request's ID — the transport-level
+// analogue of tasks/cancel — and returns ctx.Err() without waiting further.
+func (c *Conn) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	id := c.seq.Add(1)
+	var idAny interface{} = id
+	key := idKey(&idAny)
+
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("jsonrpc: encoding params for %q: %w", method, err)
+	}
+
+	ch := make(chan wireMessage, 1)
+	c.mu.Lock()
+	c.pending[key] = ch
+	c.mu.Unlock()
+
+	if err := c.stream.WriteMessage(wireMessage{JSONRPC: "2.0", ID: &idAny, Method: method, Params: paramsJSON}); err != nil {
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+		return fmt.Errorf("jsonrpc: writing %q request: %w", method, err)
+	}
+
+	select {
+	case msg := <-ch:
+		if msg.Error != nil {
+			return fmt.Errorf("jsonrpc: %s (code %d)", msg.Error.Message, msg.Error.Code)
+		}
+		if result != nil && len(msg.Result) > 0 {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				return fmt.Errorf("jsonrpc: decoding %q result: %w", method, err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+		_ = c.Notify(context.Background(), CancelMethod, cancelParams{ID: &idAny})
+		return ctx.Err()
+	}
+}
+
+// Notify sends method with params to the peer without waiting for a
+// response, e.g. to push incremental progress or, as CancelMethod, to ask
+// the peer to abandon an in-flight request.
+func (c *Conn) Notify(ctx context.Context, method string, params interface{}) error {
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("jsonrpc: encoding params for %q: %w", method, err)
+	}
+	return c.stream.WriteMessage(wireMessage{JSONRPC: "2.0", Method: method, Params: paramsJSON})
+}
+
+func (c *Conn) dispatch(ctx context.Context, handler Handler, msg wireMessage) {
+	req := &schema.JSONRPCRequest{
+		JSONRPCMessage: schema.JSONRPCMessage{
+			JSONRPCMessageIdentifier: schema.JSONRPCMessageIdentifier{ID: msg.ID},
+			JSONRPC:                  "2.0",
+		},
+		Method: msg.Method,
+		Params: msg.Params,
+	}
+
+	reqCtx := ctx
+	var cancel context.CancelFunc
+	var key string
+	if msg.ID != nil {
+		key = idKey(msg.ID)
+		reqCtx, cancel = context.WithCancel(ctx)
+		c.mu.Lock()
+		c.handling[key] = cancel
+		c.mu.Unlock()
+	}
+
+	go func() {
+		if cancel != nil {
+			defer func() {
+				c.mu.Lock()
+				delete(c.handling, key)
+				c.mu.Unlock()
+				cancel()
+			}()
+		}
+
+		var result interface{}
+		var rpcErr *schema.JSONRPCError
+		if handler != nil {
+			result, rpcErr = c.callHandler(reqCtx, handler, req)
+		} else {
+			rpcErr = &schema.JSONRPCError{Code: -32601, Message: fmt.Sprintf("Method %q not supported", msg.Method)}
+		}
+
+		if msg.ID == nil {
+			return // notification: no response expected
+		}
+
+		out := wireMessage{JSONRPC: "2.0", ID: msg.ID}
+		if rpcErr != nil {
+			out.Error = rpcErr
+		} else if resultJSON, err := json.Marshal(result); err != nil {
+			out.Error = &schema.JSONRPCError{Code: -32603, Message: "Internal error", Data: err.Error()}
+		} else {
+			out.Result = resultJSON
+		}
+		_ = c.stream.WriteMessage(out)
+	}()
+}
+
+// callHandler invokes handler, recovering a panic into a JSON-RPC internal
+// error instead of letting it crash the process — unlike net/http, which
+// recovers per-request panics for us, a panic in a goroutine is fatal to the
+// whole program by default, and dispatch runs handler in one of its own.
+func (c *Conn) callHandler(ctx context.Context, handler Handler, req *schema.JSONRPCRequest) (result interface{}, rpcErr *schema.JSONRPCError) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			rpcErr = &schema.JSONRPCError{Code: -32603, Message: "Internal error", Data: fmt.Sprintf("panic: %v", r)}
+		}
+	}()
+	return handler(ctx, c, req)
+}
+
+func (c *Conn) handleCancel(msg wireMessage) {
+	var params cancelParams
+	if len(msg.Params) > 0 {
+		_ = json.Unmarshal(msg.Params, &params)
+	}
+	if params.ID == nil {
+		return
+	}
+
+	c.mu.Lock()
+	cancel, ok := c.handling[idKey(params.ID)]
+	c.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+func (c *Conn) resolve(msg wireMessage) {
+	if msg.ID == nil {
+		return
+	}
+	key := idKey(msg.ID)
+
+	c.mu.Lock()
+	ch, ok := c.pending[key]
+	if ok {
+		delete(c.pending, key)
+	}
+	c.mu.Unlock()
+
+	if ok {
+		ch <- msg
+	}
+}
+
+// failPending delivers err to every outstanding Call so none of them hang
+// forever once the stream can no longer produce their responses.
+func (c *Conn) failPending(err error) {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[string]chan wireMessage)
+	c.mu.Unlock()
+
+	for _, ch := range pending {
+		ch <- wireMessage{Error: &schema.JSONRPCError{Code: -32000, Message: "connection closed", Data: err.Error()}}
+	}
+}
+
+// idKey renders a JSON-RPC ID into a comparable map key, keeping e.g. the
+// string "1" distinct from the number 1. An ID we generate ourselves (in
+// Call) is an int64; the same ID decoded back off the wire (in a
+// response, or in a peer's CancelMethod notification) comes back as a
+// float64, since that's what encoding/json produces for a JSON number
+// unmarshaled into an interface{}. Both must normalize to the same key.
+func idKey(id *interface{}) string {
+	switch v := (*id).(type) {
+	case int64:
+		return fmt.Sprintf("n:%v", float64(v))
+	case float64:
+		return fmt.Sprintf("n:%v", v)
+	default:
+		return fmt.Sprintf("%T:%v", v, v)
+	}
+}
@@ -0,0 +1,113 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/A2A/samples/go/jsonrpc"
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// pipeConns wires up a client and server Conn over an in-memory duplex
+// pipe and starts server's Run loop with handler, returning both ends for
+// the test to drive. Callers are responsible for closing the pipe.
+func pipeConns(t *testing.T, handler jsonrpc.Handler) (client, server *jsonrpc.Conn, close func()) {
+	t.Helper()
+	clientRWC, serverRWC := net.Pipe()
+
+	server = jsonrpc.NewConn(jsonrpc.NewStream(serverRWC))
+	client = jsonrpc.NewConn(jsonrpc.NewStream(clientRWC))
+
+	go server.Run(context.Background(), handler)
+	go client.Run(context.Background(), nil)
+
+	return client, server, func() {
+		clientRWC.Close()
+		serverRWC.Close()
+	}
+}
+
+func TestCallRoundTrip(t *testing.T) {
+	client, _, closeConns := pipeConns(t, func(ctx context.Context, conn *jsonrpc.Conn, req *schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+		var params map[string]string
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &schema.JSONRPCError{Code: -32602, Message: err.Error()}
+		}
+		return params, nil
+	})
+	defer closeConns()
+
+	var result map[string]string
+	if err := client.Call(context.Background(), "echo", map[string]string{"text": "hi"}, &result); err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if result["text"] != "hi" {
+		t.Fatalf("got %v, want text=hi", result)
+	}
+}
+
+func TestCallMethodNotFound(t *testing.T) {
+	client, _, closeConns := pipeConns(t, nil)
+	defer closeConns()
+
+	err := client.Call(context.Background(), "nonexistent", nil, nil)
+	if err == nil {
+		t.Fatal("expected an error for an unhandled method")
+	}
+}
+
+func TestCallCancellationSendsCancelNotification(t *testing.T) {
+	handlerCanceled := make(chan struct{})
+	client, _, closeConns := pipeConns(t, func(ctx context.Context, conn *jsonrpc.Conn, req *schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+		<-ctx.Done()
+		close(handlerCanceled)
+		return nil, &schema.JSONRPCError{Code: -32000, Message: "canceled"}
+	})
+	defer closeConns()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	callDone := make(chan error, 1)
+	go func() {
+		callDone <- client.Call(ctx, "slow", nil, nil)
+	}()
+
+	// Give the request time to reach the server and start running before
+	// canceling it client-side.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-handlerCanceled:
+	case <-time.After(time.Second):
+		t.Fatal("server handler's context was never canceled")
+	}
+
+	if err := <-callDone; err != context.Canceled {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestNotifyExpectsNoResponse(t *testing.T) {
+	received := make(chan string, 1)
+	client, _, closeConns := pipeConns(t, func(ctx context.Context, conn *jsonrpc.Conn, req *schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+		received <- req.Method
+		return "should be ignored", nil
+	})
+	defer closeConns()
+
+	if err := client.Notify(context.Background(), "progress", map[string]int{"percent": 50}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	select {
+	case method := <-received:
+		if method != "progress" {
+			t.Fatalf("got method %q, want %q", method, "progress")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler never saw the notification")
+	}
+}
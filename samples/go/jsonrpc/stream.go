@@ -0,0 +1,69 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Stream reads and writes framed JSON-RPC messages over a transport. Conn
+// calls ReadMessage from a single goroutine (its Run loop) but may call
+// WriteMessage concurrently from that loop, from Call, and from Handler
+// goroutines, so implementations must serialize their own writes.
+type Stream interface {
+	ReadMessage() (wireMessage, error)
+	WriteMessage(wireMessage) error
+	Close() error
+}
+
+// lineStream frames each JSON-RPC message as one newline-delimited JSON
+// value, the simplest framing that works for a byte stream like a pipe or
+// stdio. It's not suitable for a transport that fragments or reorders
+// writes below the byte-stream level (WebSocket already frames messages
+// itself and should implement Stream directly instead).
+type lineStream struct {
+	rwc     io.ReadWriteCloser
+	scanner *bufio.Scanner
+	writeMu sync.Mutex
+}
+
+// NewStream wraps rwc in a Stream that frames messages with newlines.
+func NewStream(rwc io.ReadWriteCloser) Stream {
+	scanner := bufio.NewScanner(rwc)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return &lineStream{rwc: rwc, scanner: scanner}
+}
+
+func (s *lineStream) ReadMessage() (wireMessage, error) {
+	if !s.scanner.Scan() {
+		if err := s.scanner.Err(); err != nil {
+			return wireMessage{}, err
+		}
+		return wireMessage{}, io.EOF
+	}
+
+	var msg wireMessage
+	if err := json.Unmarshal(s.scanner.Bytes(), &msg); err != nil {
+		return wireMessage{}, fmt.Errorf("jsonrpc: decoding message: %w", err)
+	}
+	return msg, nil
+}
+
+func (s *lineStream) WriteMessage(msg wireMessage) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("jsonrpc: encoding message: %w", err)
+	}
+	b = append(b, '\n')
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_, err = s.rwc.Write(b)
+	return err
+}
+
+func (s *lineStream) Close() error {
+	return s.rwc.Close()
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -8,30 +9,66 @@ import (
 	"os"
 
 	"github.com/google/A2A/samples/go/agent"
-	"github.com/google/A2A/samples/go/store"
+	"github.com/google/A2A/samples/go/auth"
+	"github.com/google/A2A/samples/go/jsonrpc"
+	"github.com/google/A2A/samples/go/schema"
+	"github.com/google/A2A/samples/go/store/storeopen"
 )
 
 func main() {
 	port := flag.Int("port", 8080, "Port to listen on")
+	storeDSN := flag.String("store", "memory://", "TaskStore DSN (memory://, postgres://..., redis://...)")
+	bearerToken := flag.String("auth-bearer-token", "", "Static Bearer token required on /a2a requests; leave empty to disable authentication")
+	stdio := flag.Bool("stdio", false, "Serve tasks/* over a newline-framed JSON-RPC Conn on stdin/stdout instead of HTTP, so tasks/sendSubscribe and tasks/resubscribe push events back as Notify calls instead of SSE")
 	flag.Parse()
 
-	// Initialize structured logger (slog)
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil)) // Logs JSON to stdout
+	// Initialize structured logger (slog). In -stdio mode, stdout is the
+	// JSON-RPC transport, so logs go to stderr instead or they'd corrupt
+	// the stream.
+	logOutput := os.Stdout
+	if *stdio {
+		logOutput = os.Stderr
+	}
+	logger := slog.New(slog.NewJSONHandler(logOutput, nil))
 	slog.SetDefault(logger)
 
 	addr := fmt.Sprintf(":%d", *port)
 
 	// Initialize dependencies
-	taskStore := store.NewInMemoryTaskStore()
-	a2aHandler := agent.NewA2AHandler(logger, taskStore)
+	taskStore, err := storeopen.Open(*storeDSN)
+	if err != nil {
+		logger.Error("Failed to open task store", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 
-	// Setup HTTP server
-	mux := http.NewServeMux()
-	mux.Handle("/a2a", a2aHandler) // Endpoint for A2A requests
+	card := schema.AgentCard{
+		Name:    "A2A Go Sample Agent",
+		URL:     addr,
+		Version: "0.1.0",
+	}
+	var authOpts []agent.AuthOption
+	if *bearerToken != "" {
+		card.Authentication = &schema.AgentAuthentication{Schemes: []string{"Bearer"}}
+		authOpts = append(authOpts,
+			agent.WithVerifier(auth.NewBearerVerifier([]string{*bearerToken})),
+			agent.WithPublicAgentCard(),
+		)
+	}
+
+	if *stdio {
+		handler := agent.NewA2AHandler(logger, taskStore)
+		conn := jsonrpc.NewConn(jsonrpc.NewStream(stdioRWC{}))
+		logger.Info("Serving A2A over stdio")
+		if err := conn.Run(context.Background(), agent.NewConnHandler(handler)); err != nil {
+			logger.Error("stdio connection ended", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		return
+	}
 
 	server := &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: agent.NewAgentServer(logger, taskStore, card, authOpts...),
 	}
 
 	logger.Info("Starting A2A Go sample agent", slog.String("address", addr), slog.String("endpoint", "/a2a"))
@@ -39,4 +76,14 @@ func main() {
 		logger.Error("Failed to start server", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
-} 
\ No newline at end of file
+}
+
+// stdioRWC adapts os.Stdin/os.Stdout to the io.ReadWriteCloser jsonrpc.NewStream
+// needs, without closing the process's actual stdio handles on Close (the
+// jsonrpc.Conn that owns it may close it before the process itself exits,
+// e.g. on a decode error it can't recover from).
+type stdioRWC struct{}
+
+func (stdioRWC) Read(p []byte) (int, error)  { return os.Stdin.Read(p) }
+func (stdioRWC) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
+func (stdioRWC) Close() error                { return nil }
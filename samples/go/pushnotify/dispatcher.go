@@ -0,0 +1,218 @@
+// Package pushnotify delivers task state transitions to the webhook URL a
+// client registered via tasks/pushNotification/set.
+package pushnotify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// Signer attaches agent-specific credentials to an outbound push
+// notification request, for schemes NewDispatcher doesn't know about
+// natively (e.g. a custom HMAC signature header).
+type Signer interface {
+	Sign(req *http.Request, task *schema.Task) error
+}
+
+// DeadLetter receives deliveries that failed every retry attempt.
+type DeadLetter interface {
+	Dead(ctx context.Context, cfg schema.PushNotificationConfig, task *schema.Task, cause error)
+}
+
+// Config configures a Dispatcher. The zero value is not usable; use
+// NewDispatcher, which fills in defaults for unset fields.
+type Config struct {
+	HTTPClient  *http.Client
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Signer      Signer
+	DeadLetter  DeadLetter
+}
+
+// Dispatcher POSTs a JSON-RPC envelope carrying the latest Task to a
+// PushNotificationConfig's URL whenever the agent core calls Notify,
+// retrying on non-2xx responses and transport errors with exponential
+// backoff and jitter.
+type Dispatcher struct {
+	logger      *slog.Logger
+	httpClient  *http.Client
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	signer      Signer
+	deadLetter  DeadLetter
+}
+
+// NewDispatcher creates a Dispatcher, defaulting MaxAttempts to 5,
+// BaseDelay to 500ms, MaxDelay to 30s, and HTTPClient to a client with a
+// 10s timeout.
+func NewDispatcher(logger *slog.Logger, cfg Config) *Dispatcher {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = 500 * time.Millisecond
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = 30 * time.Second
+	}
+	return &Dispatcher{
+		logger:      logger,
+		httpClient:  cfg.HTTPClient,
+		maxAttempts: cfg.MaxAttempts,
+		baseDelay:   cfg.BaseDelay,
+		maxDelay:    cfg.MaxDelay,
+		signer:      cfg.Signer,
+		deadLetter:  cfg.DeadLetter,
+	}
+}
+
+type pushEnvelope struct {
+	JSONRPC string       `json:"jsonrpc"`
+	Method  string       `json:"method"`
+	Params  *schema.Task `json:"params"`
+}
+
+// Notify delivers task to cfg.URL, retrying on failure up to the
+// Dispatcher's configured attempt cap. It blocks until delivery succeeds,
+// every attempt is exhausted, or ctx is canceled; callers that want
+// fire-and-forget semantics should invoke it in a goroutine.
+func (d *Dispatcher) Notify(ctx context.Context, cfg schema.PushNotificationConfig, task *schema.Task) {
+	body, err := json.Marshal(pushEnvelope{JSONRPC: "2.0", Method: "tasks/event", Params: task})
+	if err != nil {
+		d.logger.Error("pushnotify: marshal task", slog.String("task_id", task.ID), slog.String("error", err.Error()))
+		return
+	}
+
+	key := idempotencyKey(task)
+	delay := d.baseDelay
+	var lastErr error
+
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		lastErr = d.attempt(ctx, cfg, task, body, key)
+		if lastErr == nil {
+			return
+		}
+
+		d.logger.Warn("pushnotify: delivery attempt failed",
+			slog.String("task_id", task.ID),
+			slog.Int("attempt", attempt),
+			slog.Int("max_attempts", d.maxAttempts),
+			slog.String("error", lastErr.Error()))
+
+		if attempt == d.maxAttempts {
+			break
+		}
+		jitter := time.Duration(rand.Int63n(int64(delay) + 1))
+		select {
+		case <-time.After(delay + jitter):
+		case <-ctx.Done():
+			lastErr = ctx.Err()
+			attempt = d.maxAttempts
+		}
+		if delay *= 2; delay > d.maxDelay {
+			delay = d.maxDelay
+		}
+	}
+
+	d.logger.Error("pushnotify: giving up after max attempts",
+		slog.String("task_id", task.ID), slog.String("error", lastErr.Error()))
+	if d.deadLetter != nil {
+		d.deadLetter.Dead(ctx, cfg, task, lastErr)
+	}
+}
+
+// attempt performs a single delivery POST, returning nil only on a 2xx
+// response. Any other status — a 4xx rejecting the request as much as a
+// 5xx — is a failed delivery: the receiver never accepted the webhook,
+// so Notify should retry it and eventually dead-letter it like any other
+// failure, rather than treating "the server answered" as "the server
+// accepted it."
+func (d *Dispatcher) attempt(ctx context.Context, cfg schema.PushNotificationConfig, task *schema.Task, body []byte, idempotencyKey string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("pushnotify: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", idempotencyKey)
+	if err := d.authorize(req, cfg, task, body); err != nil {
+		return fmt.Errorf("pushnotify: authorize request: %w", err)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pushnotify: deliver: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("pushnotify: server returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// hmacSignatureHeader is the header name Notify's built-in "hmac" scheme
+// signs into, following the convention GitHub and Stripe webhooks use so
+// receivers can reuse an existing verification library.
+const hmacSignatureHeader = "X-Hub-Signature-256"
+
+// authorize attaches credentials per cfg.Authentication.Schemes: bearer
+// from cfg.Token, basic from cfg.Authentication.Credentials, hmac (an
+// HMAC-SHA256 of body keyed by cfg.Authentication.Credentials, written to
+// hmacSignatureHeader so the receiver can recompute and compare it), or
+// falls through to the pluggable Signer for anything else.
+func (d *Dispatcher) authorize(req *http.Request, cfg schema.PushNotificationConfig, task *schema.Task, body []byte) error {
+	if cfg.Token != nil {
+		req.Header.Set("Authorization", "Bearer "+*cfg.Token)
+		return nil
+	}
+	if cfg.Authentication != nil {
+		for _, scheme := range cfg.Authentication.Schemes {
+			switch strings.ToLower(scheme) {
+			case "basic":
+				if cfg.Authentication.Credentials != nil {
+					req.Header.Set("Authorization", "Basic "+*cfg.Authentication.Credentials)
+					return nil
+				}
+			case "hmac":
+				if cfg.Authentication.Credentials != nil {
+					mac := hmac.New(sha256.New, []byte(*cfg.Authentication.Credentials))
+					mac.Write(body)
+					req.Header.Set(hmacSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+					return nil
+				}
+			}
+		}
+	}
+	if d.signer != nil {
+		return d.signer.Sign(req, task)
+	}
+	return nil
+}
+
+// idempotencyKey derives a stable key from the fields that change
+// whenever a task transitions to a new state, so a receiver can dedupe
+// retried deliveries of the same transition.
+func idempotencyKey(task *schema.Task) string {
+	timestamp := ""
+	if task.Status.Timestamp != nil {
+		timestamp = *task.Status.Timestamp
+	}
+	return fmt.Sprintf("%s:%s:%s", task.ID, timestamp, task.Status.State)
+}
@@ -0,0 +1,150 @@
+package pushnotify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// fakeDeadLetter records the cause of the last delivery Dispatcher gave
+// up on, so tests can assert it fired instead of Notify silently
+// returning as if delivery had succeeded.
+type fakeDeadLetter struct {
+	calls int32
+	cause error
+}
+
+func (f *fakeDeadLetter) Dead(ctx context.Context, cfg schema.PushNotificationConfig, task *schema.Task, cause error) {
+	atomic.AddInt32(&f.calls, 1)
+	f.cause = cause
+}
+
+func TestNotifyDeadLettersNonSuccessResponses(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+	}{
+		{"not found", http.StatusNotFound},
+		{"unauthorized", http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			dl := &fakeDeadLetter{}
+			d := NewDispatcher(slog.Default(), Config{
+				MaxAttempts: 2,
+				BaseDelay:   time.Millisecond,
+				MaxDelay:    2 * time.Millisecond,
+				DeadLetter:  dl,
+			})
+
+			task := &schema.Task{ID: "t1", Status: schema.TaskStatus{State: schema.TaskStateWorking}}
+			d.Notify(context.Background(), schema.PushNotificationConfig{URL: server.URL}, task)
+
+			if got := atomic.LoadInt32(&dl.calls); got != 1 {
+				t.Fatalf("DeadLetter.Dead called %d times, want 1 (a %d response must not be treated as a successful delivery)", got, tc.status)
+			}
+			if dl.cause == nil {
+				t.Fatal("DeadLetter.Dead called with a nil cause")
+			}
+			if want := strconv.Itoa(tc.status); !strings.Contains(dl.cause.Error(), want) {
+				t.Errorf("cause = %q, want it to mention status %s", dl.cause.Error(), want)
+			}
+			if got := atomic.LoadInt32(&requests); got != 2 {
+				t.Errorf("server received %d requests, want 2 (MaxAttempts)", got)
+			}
+		})
+	}
+}
+
+func TestNotifySucceedsOn2xxWithoutDeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	dl := &fakeDeadLetter{}
+	d := NewDispatcher(slog.Default(), Config{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    2 * time.Millisecond,
+		DeadLetter:  dl,
+	})
+
+	task := &schema.Task{ID: "t1", Status: schema.TaskStatus{State: schema.TaskStateCompleted}}
+	d.Notify(context.Background(), schema.PushNotificationConfig{URL: server.URL}, task)
+
+	if got := atomic.LoadInt32(&dl.calls); got != 0 {
+		t.Errorf("DeadLetter.Dead called %d times, want 0 for a 202 response", got)
+	}
+}
+
+// TestNotifyHMACSignsBody proves authorize's "hmac" branch signs exactly
+// what attempt sends: the receiver must be able to recompute the same
+// sha256=<hex> digest over the raw delivered body using the shared key,
+// or every receiver relying on the documented GitHub/Stripe-style
+// verification would silently reject every delivery.
+func TestNotifyHMACSignsBody(t *testing.T) {
+	const key = "shared-hmac-key"
+
+	var gotBody []byte
+	var gotSig string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("reading request body: %v", err)
+		}
+		gotBody = body
+		gotSig = r.Header.Get(hmacSignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := NewDispatcher(slog.Default(), Config{
+		MaxAttempts: 1,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+	})
+
+	credentials := key
+	task := &schema.Task{ID: "t1", Status: schema.TaskStatus{State: schema.TaskStateCompleted}}
+	cfg := schema.PushNotificationConfig{
+		URL: server.URL,
+		Authentication: &schema.AgentAuthentication{
+			Schemes:     []string{"hmac"},
+			Credentials: &credentials,
+		},
+	}
+	d.Notify(context.Background(), cfg, task)
+
+	if gotBody == nil {
+		t.Fatal("server never received a request")
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(gotBody)
+	wantSig := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if gotSig != wantSig {
+		t.Errorf("%s = %q, want %q (recomputed over the delivered body with the shared key)", hmacSignatureHeader, gotSig, wantSig)
+	}
+}
@@ -0,0 +1,122 @@
+// Package pushreceiver implements the client side of A2A push
+// notifications: an http.Handler a terminal user points an agent's
+// webhook at (via client.Client.SetTaskPushNotification), which verifies
+// each callback's credentials, decodes it into the task it carries, and
+// hands that off to a user-supplied callback — so a long-running task
+// can be tracked without keeping its tasks/sendSubscribe stream open.
+package pushreceiver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// hmacSignatureHeader matches the header name pushnotify.Dispatcher's
+// built-in "hmac" scheme signs into.
+const hmacSignatureHeader = "X-Hub-Signature-256"
+
+// Verifier reports whether an inbound push notification request is
+// authentic, given its raw body (which a signature scheme needs to
+// recompute against). It overrides Config's Scheme-based verification
+// when set.
+type Verifier func(r *http.Request, body []byte) bool
+
+// Config configures a Handler's verification of inbound callbacks. It
+// should mirror the schema.PushNotificationConfig registered with the
+// agent via SetTaskPushNotification, so a callback is only accepted if it
+// presents the credentials the agent was told to send.
+type Config struct {
+	// Scheme selects how to verify a callback when Token is unset:
+	// "basic", "hmac", or "" to perform no verification at all.
+	Scheme string
+	// Token, if set, requires "Authorization: Bearer <Token>" and takes
+	// precedence over Scheme, so it can't be silently ignored.
+	Token string
+	// Credentials is the expected value for Scheme "basic", or the HMAC
+	// key for Scheme "hmac".
+	Credentials string
+	// Verifier, if set, is used instead of Scheme/Token/Credentials.
+	Verifier Verifier
+}
+
+// envelope is the wire shape pushnotify.Dispatcher.Notify POSTs: a
+// JSON-RPC notification whose params carry the task's new state.
+type envelope struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  schema.Task `json:"params"`
+}
+
+// NewHandler returns an http.Handler suitable for registering against
+// whatever address a terminal client listens on (e.g. the --listen flag
+// in hosts/cli). It verifies each request per cfg, decodes its body into
+// a schema.Task, and invokes onUpdate with it. A request that fails
+// verification gets 401; one that fails to decode gets 400; onUpdate is
+// not called in either case.
+func NewHandler(cfg Config, onUpdate func(*schema.Task)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "reading body", http.StatusBadRequest)
+			return
+		}
+
+		if !verify(cfg, r, body) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var env envelope
+		if err := json.Unmarshal(body, &env); err != nil {
+			http.Error(w, fmt.Sprintf("decoding task update: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		onUpdate(&env.Params)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// verify checks r (and its already-read body) against cfg, following the
+// same precedence pushnotify.Dispatcher's authorize signs a request
+// with: Token wins outright regardless of Scheme, so setting it can't be
+// silently ignored by forgetting to also set Scheme to "bearer".
+func verify(cfg Config, r *http.Request, body []byte) bool {
+	if cfg.Verifier != nil {
+		return cfg.Verifier(r, body)
+	}
+	if cfg.Token != "" {
+		return constantTimeEqual(r.Header.Get("Authorization"), "Bearer "+cfg.Token)
+	}
+	switch strings.ToLower(cfg.Scheme) {
+	case "":
+		return true
+	case "basic":
+		return constantTimeEqual(r.Header.Get("Authorization"), "Basic "+cfg.Credentials)
+	case "hmac":
+		mac := hmac.New(sha256.New, []byte(cfg.Credentials))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		return constantTimeEqual(r.Header.Get(hmacSignatureHeader), expected)
+	default:
+		return false
+	}
+}
+
+func constantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,115 @@
+package pushreceiver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifyBearer(t *testing.T) {
+	cfg := Config{Token: "s3cr3t"}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer s3cr3t")
+	if !verify(cfg, req, nil) {
+		t.Error("matching bearer token rejected")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	if verify(cfg, req, nil) {
+		t.Error("mismatched bearer token accepted")
+	}
+}
+
+func TestVerifyBasic(t *testing.T) {
+	cfg := Config{Scheme: "basic", Credentials: "dXNlcjpwYXNz"}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	if !verify(cfg, req, nil) {
+		t.Error("matching basic credentials rejected")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Basic d3Jvbmc6d3Jvbmc=")
+	if verify(cfg, req, nil) {
+		t.Error("mismatched basic credentials accepted")
+	}
+}
+
+func TestVerifyHMAC(t *testing.T) {
+	cfg := Config{Scheme: "hmac", Credentials: "hmac-key"}
+	body := []byte(`{"jsonrpc":"2.0","method":"tasks/event","params":{"id":"t1"}}`)
+
+	mac := hmac.New(sha256.New, []byte(cfg.Credentials))
+	mac.Write(body)
+	sig := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set(hmacSignatureHeader, sig)
+	if !verify(cfg, req, body) {
+		t.Error("correctly signed body rejected")
+	}
+
+	t.Run("wrong key", func(t *testing.T) {
+		wrongMac := hmac.New(sha256.New, []byte("not-the-key"))
+		wrongMac.Write(body)
+		wrongSig := "sha256=" + hex.EncodeToString(wrongMac.Sum(nil))
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(hmacSignatureHeader, wrongSig)
+		if verify(cfg, req, body) {
+			t.Error("signature computed with the wrong key accepted")
+		}
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(hmacSignatureHeader, sig)
+		tampered := append([]byte(nil), body...)
+		tampered = append(tampered, '!')
+		if verify(cfg, req, tampered) {
+			t.Error("signature valid for the original body accepted for a tampered one")
+		}
+	})
+}
+
+func TestVerifyVerifierOverridesScheme(t *testing.T) {
+	called := false
+	cfg := Config{
+		Scheme:      "hmac",
+		Credentials: "hmac-key",
+		Verifier: func(r *http.Request, body []byte) bool {
+			called = true
+			return true
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	if !verify(cfg, req, []byte("anything")) {
+		t.Error("Verifier returning true was not honored")
+	}
+	if !called {
+		t.Error("Verifier override was not invoked")
+	}
+}
+
+func TestVerifyEmptySchemeAcceptsEverything(t *testing.T) {
+	cfg := Config{}
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	if !verify(cfg, req, []byte("anything")) {
+		t.Error("Scheme == \"\" must accept every request (that's the no-verification default callers opt into)")
+	}
+}
+
+func TestVerifyUnknownSchemeRejects(t *testing.T) {
+	cfg := Config{Scheme: "wat"}
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	if verify(cfg, req, []byte("anything")) {
+		t.Error("unrecognized Scheme must not be treated as authenticated")
+	}
+}
@@ -0,0 +1,71 @@
+package rpcserver
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Recover wraps next so a panic inside it is encoded as a JSON-RPC
+// internal error instead of the request's connection being dropped.
+// net/http already recovers a
+// panicking handler enough to keep the server itself alive, but without
+// this the client still gets a connection reset rather than a
+// well-formed error response.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				JSONError(w, nil, -32603, "Internal error", fmt.Sprintf("panic: %v", rec))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CORS wraps next so cross-origin requests from origin are allowed to
+// call this JSON-RPC endpoint, answering a preflight OPTIONS request
+// itself rather than passing it through.
+func CORS(origin string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			if r.Method == http.MethodOptions {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Metrics counts requests per JSON-RPC method. Pass one to
+// NewMethodRouter to have it counted automatically; the zero value is
+// ready to use.
+type Metrics struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewMetrics creates an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{counts: make(map[string]int64)}
+}
+
+func (m *Metrics) inc(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int64)
+	}
+	m.counts[method]++
+}
+
+// Count returns how many requests have been recorded for method.
+func (m *Metrics) Count(method string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[method]
+}
@@ -0,0 +1,136 @@
+package rpcserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/A2A/samples/go/rpcserver"
+	"github.com/google/A2A/samples/go/schema"
+)
+
+func postJSON(t *testing.T, h http.Handler, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder) schema.JSONRPCResponse {
+	t.Helper()
+	var resp schema.JSONRPCResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding response: %v (body: %s)", err, rec.Body.String())
+	}
+	return resp
+}
+
+func TestMethodRouterDispatchesRegisteredMethod(t *testing.T) {
+	router := rpcserver.NewMethodRouter(nil, nil)
+	router.Handle("echo", func(ctx context.Context, req schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+		var params map[string]string
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &schema.JSONRPCError{Code: -32602, Message: err.Error()}
+		}
+		return params, nil
+	})
+
+	rec := postJSON(t, router, `{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"hi"}}`)
+	resp := decodeResponse(t, rec)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+
+	result, ok := resp.Result.(map[string]interface{})
+	if !ok || result["text"] != "hi" {
+		t.Fatalf("got result %+v, want text=hi", resp.Result)
+	}
+}
+
+func TestMethodRouterUnknownMethod(t *testing.T) {
+	router := rpcserver.NewMethodRouter(nil, nil)
+
+	rec := postJSON(t, router, `{"jsonrpc":"2.0","id":1,"method":"nonexistent"}`)
+	resp := decodeResponse(t, rec)
+	if resp.Error == nil || resp.Error.Code != -32601 {
+		t.Fatalf("got error %+v, want code -32601", resp.Error)
+	}
+}
+
+func TestMethodRouterMalformedRequest(t *testing.T) {
+	router := rpcserver.NewMethodRouter(nil, nil)
+
+	rec := postJSON(t, router, `not json`)
+	resp := decodeResponse(t, rec)
+	if resp.Error == nil || resp.Error.Code != -32700 {
+		t.Fatalf("got error %+v, want code -32700", resp.Error)
+	}
+}
+
+func TestMethodRouterHandlerError(t *testing.T) {
+	router := rpcserver.NewMethodRouter(nil, nil)
+	router.Handle("fail", func(ctx context.Context, req schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+		return nil, &schema.JSONRPCError{Code: -32001, Message: "Task not found"}
+	})
+
+	rec := postJSON(t, router, `{"jsonrpc":"2.0","id":1,"method":"fail"}`)
+	resp := decodeResponse(t, rec)
+	if resp.Error == nil || resp.Error.Code != -32001 {
+		t.Fatalf("got error %+v, want code -32001", resp.Error)
+	}
+}
+
+func TestMetricsCountsDispatchedRequests(t *testing.T) {
+	metrics := rpcserver.NewMetrics()
+	router := rpcserver.NewMethodRouter(nil, metrics)
+	router.Handle("echo", func(ctx context.Context, req schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+		return nil, nil
+	})
+
+	postJSON(t, router, `{"jsonrpc":"2.0","id":1,"method":"echo"}`)
+	postJSON(t, router, `{"jsonrpc":"2.0","id":2,"method":"echo"}`)
+	postJSON(t, router, `{"jsonrpc":"2.0","id":3,"method":"nonexistent"}`)
+
+	if got := metrics.Count("echo"); got != 2 {
+		t.Fatalf("Count(echo) = %d, want 2", got)
+	}
+	if got := metrics.Count("nonexistent"); got != 1 {
+		t.Fatalf("Count(nonexistent) = %d, want 1", got)
+	}
+}
+
+func TestRecoverConvertsPanicToInternalError(t *testing.T) {
+	router := rpcserver.NewMethodRouter(nil, nil)
+	router.Handle("explode", func(ctx context.Context, req schema.JSONRPCRequest) (interface{}, *schema.JSONRPCError) {
+		panic("boom")
+	})
+
+	rec := postJSON(t, rpcserver.Recover(router), `{"jsonrpc":"2.0","id":1,"method":"explode"}`)
+	resp := decodeResponse(t, rec)
+	if resp.Error == nil || resp.Error.Code != -32603 {
+		t.Fatalf("got error %+v, want code -32603", resp.Error)
+	}
+}
+
+func TestCORSAnswersPreflightWithoutCallingNext(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	rec := httptest.NewRecorder()
+	rpcserver.CORS("*")(next).ServeHTTP(rec, req)
+
+	if called {
+		t.Fatal("CORS should not call next for an OPTIONS preflight")
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusNoContent)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, "*")
+	}
+}
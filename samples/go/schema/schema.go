@@ -1,6 +1,9 @@
 package schema
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // TaskState represents the state of a task.
 // Corresponds to the TaskState enum in the A2A schema.
@@ -30,7 +33,7 @@ type JSONRPCMessage struct {
 // JSONRPCRequest represents a JSON-RPC request.
 type JSONRPCRequest struct {
 	JSONRPCMessage
-	Method string           `json:"method"`
+	Method string          `json:"method"`
 	Params json.RawMessage `json:"params,omitempty"` // Use RawMessage to delay parsing
 }
 
@@ -71,13 +74,13 @@ type AgentProvider struct {
 
 // AgentSkill defines a specific skill.
 type AgentSkill struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description *string   `json:"description,omitempty"`
-	Tags        []string  `json:"tags,omitempty"`
-	Examples    []string  `json:"examples,omitempty"`
-	InputModes  []string  `json:"inputModes,omitempty"`
-	OutputModes []string  `json:"outputModes,omitempty"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Examples    []string `json:"examples,omitempty"`
+	InputModes  []string `json:"inputModes,omitempty"`
+	OutputModes []string `json:"outputModes,omitempty"`
 }
 
 // AgentCard represents the metadata card for an agent.
@@ -104,7 +107,7 @@ type FileContentBase struct {
 // FileContentBytes represents file content as base64 bytes.
 type FileContentBytes struct {
 	FileContentBase
-	Bytes string `json:"bytes"` // Required
+	Bytes string `json:"bytes"`         // Required
 	URI   string `json:"uri,omitempty"` // Should be omitted if bytes is present
 }
 
@@ -112,40 +115,180 @@ type FileContentBytes struct {
 type FileContentUri struct {
 	FileContentBase
 	Bytes string `json:"bytes,omitempty"` // Should be omitted if uri is present
-	URI   string `json:"uri"` // Required
+	URI   string `json:"uri"`             // Required
+}
+
+// FileContent is implemented by FileContentBytes and FileContentUri. It is
+// sealed so that unmarshalFileContent is the only place new variants can surface.
+type FileContent interface {
+	fileContentTag() string
 }
 
-// FileContent represents file content (either bytes or URI).
-// Using interface{} because the JSON can be either FileContentBytes or FileContentUri.
-// A consuming agent would use a type switch or type assertion to handle the specific type.
-type FileContent interface{}
+func (FileContentBytes) fileContentTag() string { return "bytes" }
+func (FileContentUri) fileContentTag() string   { return "uri" }
+
+// unmarshalFileContent picks FileContentBytes or FileContentUri based on
+// which of "bytes"/"uri" is present, erroring if both or neither are set.
+func unmarshalFileContent(raw json.RawMessage) (FileContent, error) {
+	var probe struct {
+		Bytes *string `json:"bytes"`
+		URI   *string `json:"uri"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return nil, fmt.Errorf("schema: decode file content: %w", err)
+	}
+	switch {
+	case probe.Bytes != nil && probe.URI != nil:
+		return nil, fmt.Errorf("schema: file content has both bytes and uri set")
+	case probe.Bytes != nil:
+		var fc FileContentBytes
+		if err := json.Unmarshal(raw, &fc); err != nil {
+			return nil, fmt.Errorf("schema: decode FileContentBytes: %w", err)
+		}
+		return fc, nil
+	case probe.URI != nil:
+		var fc FileContentUri
+		if err := json.Unmarshal(raw, &fc); err != nil {
+			return nil, fmt.Errorf("schema: decode FileContentUri: %w", err)
+		}
+		return fc, nil
+	default:
+		return nil, fmt.Errorf("schema: file content has neither bytes nor uri set")
+	}
+}
 
 // TextPart represents a text part of a message.
 type TextPart struct {
-	Type     string                 `json:"type,omitempty"` // Should be "text"
+	Type     string                 `json:"type"` // Always "text"
 	Text     string                 `json:"text"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // FilePart represents a file part of a message.
 type FilePart struct {
-	Type     string                 `json:"type,omitempty"` // Should be "file"
+	Type     string                 `json:"type"` // Always "file"
 	File     FileContent            `json:"file"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// filePartAlias lets FilePart marshal/unmarshal its Metadata and Type
+// normally while File is handled separately as a FileContent union.
+type filePartAlias struct {
+	Type     string                 `json:"type"`
+	File     json.RawMessage        `json:"file"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// MarshalJSON emits FilePart.File as whichever concrete FileContent variant it holds.
+func (p FilePart) MarshalJSON() ([]byte, error) {
+	fileBytes, err := json.Marshal(p.File)
+	if err != nil {
+		return nil, fmt.Errorf("schema: marshal file content: %w", err)
+	}
+	return json.Marshal(filePartAlias{
+		Type:     "file",
+		File:     fileBytes,
+		Metadata: p.Metadata,
+	})
+}
+
+// UnmarshalJSON decodes FilePart.File into the concrete FileContent variant.
+func (p *FilePart) UnmarshalJSON(data []byte) error {
+	var alias filePartAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return fmt.Errorf("schema: decode FilePart: %w", err)
+	}
+	file, err := unmarshalFileContent(alias.File)
+	if err != nil {
+		return err
+	}
+	p.Type = "file"
+	p.File = file
+	p.Metadata = alias.Metadata
+	return nil
+}
+
 // DataPart represents a structured data part of a message.
 type DataPart struct {
-	Type     string                 `json:"type,omitempty"` // Should be "data"
+	Type     string                 `json:"type"` // Always "data"
 	Data     map[string]interface{} `json:"data"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// Part represents any part of a message (text, file, data).
-// Using interface{} because the JSON can be TextPart, FilePart, or DataPart.
-// A consuming agent would use a type switch or type assertion to determine the actual
-// type of the part and access its specific fields (e.g., part.(TextPart).Text).
-type Part interface{}
+// Part is a sealed interface implemented by TextPart, FilePart, and DataPart.
+// Consumers can type-switch on the concrete type rather than on map[string]interface{}.
+type Part interface {
+	partType() string
+}
+
+func (TextPart) partType() string { return "text" }
+func (FilePart) partType() string { return "file" }
+func (DataPart) partType() string { return "data" }
+
+// MarshalJSON ensures TextPart always emits its discriminator, even if the
+// caller left Type unset.
+func (p TextPart) MarshalJSON() ([]byte, error) {
+	type alias TextPart
+	a := alias(p)
+	a.Type = "text"
+	return json.Marshal(a)
+}
+
+// MarshalJSON ensures DataPart always emits its discriminator, even if the
+// caller left Type unset.
+func (p DataPart) MarshalJSON() ([]byte, error) {
+	type alias DataPart
+	a := alias(p)
+	a.Type = "data"
+	return json.Marshal(a)
+}
+
+// unmarshalPart peeks at the "type" discriminator in raw and dispatches to
+// the concrete Part implementation. Shared by Message and Artifact so both
+// decode parts identically.
+func unmarshalPart(raw json.RawMessage) (Part, error) {
+	var probe struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return nil, fmt.Errorf("schema: decode part: %w", err)
+	}
+	switch probe.Type {
+	case "text":
+		var p TextPart
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, fmt.Errorf("schema: decode TextPart: %w", err)
+		}
+		return p, nil
+	case "file":
+		var p FilePart
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, fmt.Errorf("schema: decode FilePart: %w", err)
+		}
+		return p, nil
+	case "data":
+		var p DataPart
+		if err := json.Unmarshal(raw, &p); err != nil {
+			return nil, fmt.Errorf("schema: decode DataPart: %w", err)
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("schema: unknown part type %q", probe.Type)
+	}
+}
+
+// unmarshalRawParts decodes each already-split element of a parts array via unmarshalPart.
+func unmarshalRawParts(raw []json.RawMessage) ([]Part, error) {
+	parts := make([]Part, 0, len(raw))
+	for _, r := range raw {
+		p, err := unmarshalPart(r)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, p)
+	}
+	return parts, nil
+}
 
 // Artifact represents an artifact generated or used by a task.
 type Artifact struct {
@@ -158,6 +301,38 @@ type Artifact struct {
 	LastChunk   *bool                  `json:"lastChunk,omitempty"`
 }
 
+// artifactAlias lets Artifact unmarshal its non-Parts fields normally while
+// Parts is decoded through unmarshalPart.
+type artifactAlias struct {
+	Name        *string                `json:"name,omitempty"`
+	Description *string                `json:"description,omitempty"`
+	Parts       []json.RawMessage      `json:"parts"`
+	Index       *int                   `json:"index,omitempty"`
+	Append      *bool                  `json:"append,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	LastChunk   *bool                  `json:"lastChunk,omitempty"`
+}
+
+// UnmarshalJSON dispatches each element of Parts to its concrete Part type.
+func (a *Artifact) UnmarshalJSON(data []byte) error {
+	var alias artifactAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return fmt.Errorf("schema: decode Artifact: %w", err)
+	}
+	parts, err := unmarshalRawParts(alias.Parts)
+	if err != nil {
+		return err
+	}
+	a.Name = alias.Name
+	a.Description = alias.Description
+	a.Parts = parts
+	a.Index = alias.Index
+	a.Append = alias.Append
+	a.Metadata = alias.Metadata
+	a.LastChunk = alias.LastChunk
+	return nil
+}
+
 // Message represents a message exchanged between user and agent.
 type Message struct {
 	Role     string                 `json:"role"` // "user" or "agent"
@@ -165,6 +340,30 @@ type Message struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// messageAlias lets Message unmarshal Role and Metadata normally while Parts
+// is decoded through unmarshalPart.
+type messageAlias struct {
+	Role     string                 `json:"role"`
+	Parts    []json.RawMessage      `json:"parts"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// UnmarshalJSON dispatches each element of Parts to its concrete Part type.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var alias messageAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return fmt.Errorf("schema: decode Message: %w", err)
+	}
+	parts, err := unmarshalRawParts(alias.Parts)
+	if err != nil {
+		return err
+	}
+	m.Role = alias.Role
+	m.Parts = parts
+	m.Metadata = alias.Metadata
+	return nil
+}
+
 // TaskStatus represents the status of a task.
 type TaskStatus struct {
 	State     TaskState `json:"state"`
@@ -174,11 +373,15 @@ type TaskStatus struct {
 
 // Task represents a task being processed.
 type Task struct {
-	ID        string                 `json:"id"`
-	SessionID *string                `json:"sessionId,omitempty"`
-	Status    TaskStatus             `json:"status"`
-	Artifacts []Artifact             `json:"artifacts,omitempty"`
-	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	ID        string     `json:"id"`
+	SessionID *string    `json:"sessionId,omitempty"`
+	Status    TaskStatus `json:"status"`
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	// History holds up to TaskQueryParams.HistoryLength of the task's
+	// most recent messages, populated by tasks/get; it is empty unless
+	// explicitly requested.
+	History  []Message              `json:"history,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // TaskHistory represents the message history of a task.
@@ -186,10 +389,27 @@ type TaskHistory struct {
 	MessageHistory []Message `json:"messageHistory,omitempty"`
 }
 
+// TaskStatusUpdateEvent is streamed to tasks/sendSubscribe and
+// tasks/resubscribe subscribers whenever a task's status changes.
+type TaskStatusUpdateEvent struct {
+	ID       string                 `json:"id"`
+	Status   TaskStatus             `json:"status"`
+	Final    bool                   `json:"final"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// TaskArtifactUpdateEvent is streamed to tasks/sendSubscribe and
+// tasks/resubscribe subscribers whenever a task produces or appends an artifact.
+type TaskArtifactUpdateEvent struct {
+	ID       string                 `json:"id"`
+	Artifact Artifact               `json:"artifact"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // PushNotificationConfig defines push notification settings.
 type PushNotificationConfig struct {
-	URL            string                `json:"url"`
-	Token          *string               `json:"token,omitempty"`
+	URL            string               `json:"url"`
+	Token          *string              `json:"token,omitempty"`
 	Authentication *AgentAuthentication `json:"authentication,omitempty"` // Reusing AgentAuthentication for simplicity
 }
 
@@ -197,12 +417,19 @@ type PushNotificationConfig struct {
 
 // TaskSendParams are parameters for the tasks/send method.
 type TaskSendParams struct {
-	ID             string                  `json:"id"`
-	SessionID      *string                 `json:"sessionId,omitempty"`
-	Message        Message                 `json:"message"`
+	ID               string                  `json:"id"`
+	SessionID        *string                 `json:"sessionId,omitempty"`
+	Message          Message                 `json:"message"`
 	PushNotification *PushNotificationConfig `json:"pushNotification,omitempty"`
-	HistoryLength  *int                    `json:"historyLength,omitempty"`
-	Metadata       map[string]interface{}  `json:"metadata,omitempty"`
+	HistoryLength    *int                    `json:"historyLength,omitempty"`
+	Metadata         map[string]interface{}  `json:"metadata,omitempty"`
+
+	// Deadline, if set, is an RFC3339 timestamp after which the task is
+	// automatically canceled if it's still running. TimeoutMs is a
+	// convenience alternative equivalent to a Deadline of now+TimeoutMs;
+	// if both are set, Deadline takes precedence.
+	Deadline  *string `json:"deadline,omitempty"`
+	TimeoutMs *int    `json:"timeoutMs,omitempty"`
 }
 
 // TaskIdParams are parameters used for operations needing only a task ID.
@@ -219,6 +446,6 @@ type TaskQueryParams struct {
 
 // TaskPushNotificationConfig includes task ID and push config.
 type TaskPushNotificationConfig struct {
-	ID                   string                 `json:"id"`
+	ID                     string                 `json:"id"`
 	PushNotificationConfig PushNotificationConfig `json:"pushNotificationConfig"`
-} 
\ No newline at end of file
+}
@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMessagePartsRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		json string
+		want Part
+	}{
+		{
+			name: "text",
+			json: `{"type":"text","text":"hello"}`,
+			want: TextPart{Type: "text", Text: "hello"},
+		},
+		{
+			name: "file with bytes",
+			json: `{"type":"file","file":{"name":"a.txt","bytes":"aGk="}}`,
+			want: FilePart{Type: "file", File: FileContentBytes{
+				FileContentBase: FileContentBase{Name: strPtr("a.txt")},
+				Bytes:           "aGk=",
+			}},
+		},
+		{
+			name: "file with uri",
+			json: `{"type":"file","file":{"mimeType":"image/png","uri":"https://example.com/a.png"}}`,
+			want: FilePart{Type: "file", File: FileContentUri{
+				FileContentBase: FileContentBase{MimeType: strPtr("image/png")},
+				URI:             "https://example.com/a.png",
+			}},
+		},
+		{
+			name: "data",
+			json: `{"type":"data","data":{"k":"v"}}`,
+			want: DataPart{Type: "data", Data: map[string]interface{}{"k": "v"}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			msgJSON := `{"role":"user","parts":[` + tc.json + `]}`
+
+			var msg Message
+			if err := json.Unmarshal([]byte(msgJSON), &msg); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if len(msg.Parts) != 1 {
+				t.Fatalf("expected 1 part, got %d", len(msg.Parts))
+			}
+			if got, want := msg.Parts[0], tc.want; !partsEqual(t, got, want) {
+				t.Errorf("got part %#v, want %#v", got, want)
+			}
+
+			out, err := json.Marshal(msg)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var roundTripped Message
+			if err := json.Unmarshal(out, &roundTripped); err != nil {
+				t.Fatalf("Unmarshal after round-trip: %v", err)
+			}
+			if !partsEqual(t, roundTripped.Parts[0], tc.want) {
+				t.Errorf("after round-trip got %#v, want %#v", roundTripped.Parts[0], tc.want)
+			}
+		})
+	}
+}
+
+func TestMessagePartsMixedArray(t *testing.T) {
+	msgJSON := `{"role":"agent","parts":[
+		{"type":"text","text":"here is the file"},
+		{"type":"file","file":{"uri":"https://example.com/x"}},
+		{"type":"data","data":{"rows":1}}
+	]}`
+
+	var msg Message
+	if err := json.Unmarshal([]byte(msgJSON), &msg); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(msg.Parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(msg.Parts))
+	}
+	if _, ok := msg.Parts[0].(TextPart); !ok {
+		t.Errorf("part 0: expected TextPart, got %T", msg.Parts[0])
+	}
+	if _, ok := msg.Parts[1].(FilePart); !ok {
+		t.Errorf("part 1: expected FilePart, got %T", msg.Parts[1])
+	}
+	if _, ok := msg.Parts[2].(DataPart); !ok {
+		t.Errorf("part 2: expected DataPart, got %T", msg.Parts[2])
+	}
+}
+
+func TestArtifactPartsMixedArray(t *testing.T) {
+	artifactJSON := `{"parts":[
+		{"type":"text","text":"result"},
+		{"type":"data","data":{"ok":true}}
+	]}`
+
+	var artifact Artifact
+	if err := json.Unmarshal([]byte(artifactJSON), &artifact); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if len(artifact.Parts) != 2 {
+		t.Fatalf("expected 2 parts, got %d", len(artifact.Parts))
+	}
+	if _, ok := artifact.Parts[0].(TextPart); !ok {
+		t.Errorf("part 0: expected TextPart, got %T", artifact.Parts[0])
+	}
+	if _, ok := artifact.Parts[1].(DataPart); !ok {
+		t.Errorf("part 1: expected DataPart, got %T", artifact.Parts[1])
+	}
+}
+
+func TestFilePartBothBytesAndURIErrors(t *testing.T) {
+	var msg Message
+	err := json.Unmarshal([]byte(`{"role":"user","parts":[{"type":"file","file":{"bytes":"aGk=","uri":"https://x"}}]}`), &msg)
+	if err == nil {
+		t.Fatal("expected error for file content with both bytes and uri set")
+	}
+}
+
+func TestFilePartNeitherBytesNorURIErrors(t *testing.T) {
+	var msg Message
+	err := json.Unmarshal([]byte(`{"role":"user","parts":[{"type":"file","file":{"name":"a.txt"}}]}`), &msg)
+	if err == nil {
+		t.Fatal("expected error for file content with neither bytes nor uri set")
+	}
+}
+
+func TestUnknownPartTypeErrors(t *testing.T) {
+	var msg Message
+	err := json.Unmarshal([]byte(`{"role":"user","parts":[{"type":"video","url":"https://x"}]}`), &msg)
+	if err == nil {
+		t.Fatal("expected error for unknown part type")
+	}
+}
+
+func partsEqual(t *testing.T, got, want Part) bool {
+	t.Helper()
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Marshal got: %v", err)
+	}
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal want: %v", err)
+	}
+	return string(gotJSON) == string(wantJSON)
+}
+
+func strPtr(s string) *string { return &s }
@@ -41,6 +41,13 @@ func (s *A2AServer) Start() error {
 }
 
 // handleRequest handles incoming HTTP requests
+//
+// TODO(rpcserver): this duplicates the parse/route/encode logic
+// rpcserver.MethodRouter now provides for agent.A2AHandler. It isn't
+// folded onto that router yet because this package's a2a/models types
+// have no shared module manifest with rpcserver's schema package — see
+// rpcserver's package doc for the full reasoning. Migrate this once
+// models and schema are reconciled.
 func (s *A2AServer) handleRequest(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
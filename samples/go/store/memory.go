@@ -6,22 +6,24 @@ import (
 	"github.com/google/A2A/samples/go/schema"
 )
 
-// TaskStore defines the interface for storing and retrieving A2A tasks.
-type TaskStore interface {
-	Get(id string) (*schema.Task, bool)
-	Put(task *schema.Task)
-}
-
-// InMemoryTaskStore implements TaskStore using an in-memory map.
+// InMemoryTaskStore implements TaskStore using in-memory maps. It is the
+// default backend and the reference implementation every other TaskStore
+// is conformance-tested against.
 type InMemoryTaskStore struct {
-	mu    sync.RWMutex
-	tasks map[string]*schema.Task
+	mu          sync.RWMutex
+	tasks       map[string]*schema.Task
+	history     map[string][]schema.Message
+	pushConfigs map[string]schema.PushNotificationConfig
+	*PubSub
 }
 
 // NewInMemoryTaskStore creates a new InMemoryTaskStore.
 func NewInMemoryTaskStore() *InMemoryTaskStore {
 	return &InMemoryTaskStore{
-		tasks: make(map[string]*schema.Task),
+		tasks:       make(map[string]*schema.Task),
+		history:     make(map[string][]schema.Message),
+		pushConfigs: make(map[string]schema.PushNotificationConfig),
+		PubSub:      NewPubSub(),
 	}
 }
 
@@ -38,4 +40,75 @@ func (s *InMemoryTaskStore) Put(task *schema.Task) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.tasks[task.ID] = task
-} 
\ No newline at end of file
+}
+
+// List returns the tasks matching filter.
+func (s *InMemoryTaskStore) List(filter TaskFilter) ([]*schema.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var tasks []*schema.Task
+	for _, task := range s.tasks {
+		if matchesFilter(task, filter) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func matchesFilter(task *schema.Task, filter TaskFilter) bool {
+	if filter.SessionID != nil && (task.SessionID == nil || *task.SessionID != *filter.SessionID) {
+		return false
+	}
+	if filter.State != nil && task.Status.State != *filter.State {
+		return false
+	}
+	return true
+}
+
+// Delete removes a task along with its history, artifacts and push config.
+func (s *InMemoryTaskStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tasks, id)
+	delete(s.history, id)
+	delete(s.pushConfigs, id)
+	return nil
+}
+
+// AppendHistory records msg as the next message in task id's history.
+func (s *InMemoryTaskStore) AppendHistory(id string, msg schema.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history[id] = append(s.history[id], msg)
+	return nil
+}
+
+// History returns the last limit messages recorded for task id.
+func (s *InMemoryTaskStore) History(id string, limit int) ([]schema.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	msgs := s.history[id]
+	if limit <= 0 || limit >= len(msgs) {
+		out := make([]schema.Message, len(msgs))
+		copy(out, msgs)
+		return out, nil
+	}
+	out := make([]schema.Message, limit)
+	copy(out, msgs[len(msgs)-limit:])
+	return out, nil
+}
+
+// SetPushConfig stores the push notification config registered for id.
+func (s *InMemoryTaskStore) SetPushConfig(id string, cfg schema.PushNotificationConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pushConfigs[id] = cfg
+}
+
+// GetPushConfig retrieves the push notification config registered for id.
+func (s *InMemoryTaskStore) GetPushConfig(id string) (schema.PushNotificationConfig, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cfg, ok := s.pushConfigs[id]
+	return cfg, ok
+}
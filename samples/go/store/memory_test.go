@@ -0,0 +1,14 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/google/A2A/samples/go/store"
+	"github.com/google/A2A/samples/go/store/storetest"
+)
+
+func TestInMemoryTaskStoreConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) store.TaskStore {
+		return store.NewInMemoryTaskStore()
+	})
+}
@@ -0,0 +1,226 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// TaskEvent is a single update delivered to tasks/sendSubscribe and
+// tasks/resubscribe subscribers. Exactly one of StatusUpdate or
+// ArtifactUpdate is set.
+type TaskEvent struct {
+	StatusUpdate   *schema.TaskStatusUpdateEvent
+	ArtifactUpdate *schema.TaskArtifactUpdateEvent
+}
+
+// Final reports whether this event terminates the stream for its task.
+func (e TaskEvent) Final() bool {
+	return e.StatusUpdate != nil && e.StatusUpdate.Final
+}
+
+// subscriberBacklogSize bounds how many non-terminal "working" status
+// updates a slow subscriber can fall behind by before the oldest is
+// dropped in favor of the newest. Artifact updates and terminal status
+// updates are never subject to this limit.
+const subscriberBacklogSize = 16
+
+// subscriber buffers events for one tasks/sendSubscribe or
+// tasks/resubscribe listener. It decouples the publisher (the task
+// runner) from however fast the listener can drain its HTTP response.
+type subscriber struct {
+	mu     sync.Mutex
+	events []TaskEvent
+	notify chan struct{}
+	closed bool
+}
+
+func newSubscriber() *subscriber {
+	return &subscriber{notify: make(chan struct{}, 1)}
+}
+
+// deliver appends ev to the backlog, evicting the oldest droppable
+// (non-terminal, non-artifact) event first if the backlog is full.
+func (s *subscriber) deliver(ev TaskEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if len(s.events) >= subscriberBacklogSize {
+		if idx := s.indexOfOldestDroppable(); idx >= 0 {
+			s.events = append(s.events[:idx], s.events[idx+1:]...)
+		}
+	}
+	s.events = append(s.events, ev)
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// indexOfOldestDroppable returns the index of the oldest buffered event
+// that is safe to drop (a non-terminal status update), or -1 if every
+// buffered event must be delivered.
+func (s *subscriber) indexOfOldestDroppable() int {
+	for i, ev := range s.events {
+		if ev.ArtifactUpdate == nil && !ev.Final() {
+			return i
+		}
+	}
+	return -1
+}
+
+// drain removes and returns all currently buffered events.
+func (s *subscriber) drain() []TaskEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := s.events
+	s.events = nil
+	return events
+}
+
+func (s *subscriber) close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+}
+
+// taskBroadcast fans the events for one task out to every attached
+// subscriber, so a single task runner can serve a sendSubscribe caller
+// plus any number of resubscribe callers.
+type taskBroadcast struct {
+	mu      sync.Mutex
+	subs    map[int]*subscriber
+	next    int
+	onEmpty func()
+}
+
+// newTaskBroadcast creates a taskBroadcast that calls onEmpty, if
+// non-nil, every time remove drops its last subscriber — so PubSub can
+// evict its own map entry instead of keeping it forever.
+func newTaskBroadcast(onEmpty func()) *taskBroadcast {
+	return &taskBroadcast{subs: make(map[int]*subscriber), onEmpty: onEmpty}
+}
+
+func (b *taskBroadcast) publish(ev TaskEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range b.subs {
+		sub.deliver(ev)
+	}
+}
+
+func (b *taskBroadcast) add() (id int, sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id = b.next
+	b.next++
+	sub = newSubscriber()
+	b.subs[id] = sub
+	return id, sub
+}
+
+func (b *taskBroadcast) remove(id int) {
+	b.mu.Lock()
+	sub, ok := b.subs[id]
+	if ok {
+		delete(b.subs, id)
+	}
+	empty := len(b.subs) == 0
+	b.mu.Unlock()
+	if ok {
+		sub.close()
+	}
+	if empty && b.onEmpty != nil {
+		b.onEmpty()
+	}
+}
+
+// PubSub provides publish/subscribe fan-out for a task's event stream.
+// InMemoryTaskStore embeds it so tasks/sendSubscribe and
+// tasks/resubscribe share one task-runner-to-subscribers broadcast per
+// task id.
+type PubSub struct {
+	mu         sync.Mutex
+	broadcasts map[string]*taskBroadcast
+}
+
+// NewPubSub creates an empty PubSub.
+func NewPubSub() *PubSub {
+	return &PubSub{broadcasts: make(map[string]*taskBroadcast)}
+}
+
+func (p *PubSub) broadcastFor(id string) *taskBroadcast {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b, ok := p.broadcasts[id]
+	if !ok {
+		var created *taskBroadcast
+		created = newTaskBroadcast(func() { p.evict(id, created) })
+		b = created
+		p.broadcasts[id] = b
+	}
+	return b
+}
+
+// evict removes id's entry once b, the taskBroadcast that just lost its
+// last subscriber, has no one left to read it, whether or not a terminal
+// event was published — but only if it's still the same instance: a concurrent
+// Subscribe or Publish may have already replaced it with a fresh
+// taskBroadcast after the last subscriber detached, and evicting that
+// newer one would drop whoever just attached to it.
+func (p *PubSub) evict(id string, b *taskBroadcast) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.broadcasts[id] == b {
+		delete(p.broadcasts, id)
+	}
+}
+
+// Publish fans out ev to every subscriber currently attached to id.
+func (p *PubSub) Publish(id string, ev TaskEvent) {
+	p.broadcastFor(id).publish(ev)
+}
+
+// Subscribe attaches a new listener to id's event stream. events yields
+// buffered TaskEvents until cancel is called or a terminal event is
+// delivered, at which point events is closed.
+func (p *PubSub) Subscribe(id string) (events <-chan TaskEvent, cancel func()) {
+	b := p.broadcastFor(id)
+	subID, sub := b.add()
+
+	out := make(chan TaskEvent)
+	done := make(chan struct{})
+	var closeOnce sync.Once
+	cancelFn := func() {
+		closeOnce.Do(func() {
+			close(done)
+			b.remove(subID)
+		})
+	}
+
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-sub.notify:
+				for _, ev := range sub.drain() {
+					select {
+					case out <- ev:
+						if ev.Final() {
+							cancelFn()
+							return
+						}
+					case <-done:
+						return
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return out, cancelFn
+}
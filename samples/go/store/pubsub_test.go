@@ -0,0 +1,76 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/google/A2A/samples/go/schema"
+)
+
+func pubSubLen(p *PubSub) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.broadcasts)
+}
+
+// TestPubSubEvictsBroadcastAfterLastSubscriberDetaches proves
+// broadcastFor's lazily-created *taskBroadcast doesn't outlive every
+// subscriber that ever attached to it — otherwise a long-running agent
+// leaks one map entry per completed task forever.
+func TestPubSubEvictsBroadcastAfterLastSubscriberDetaches(t *testing.T) {
+	p := NewPubSub()
+
+	events, cancel := p.Subscribe("t1")
+	if got := pubSubLen(p); got != 1 {
+		t.Fatalf("broadcasts len = %d after Subscribe, want 1", got)
+	}
+
+	p.Publish("t1", TaskEvent{StatusUpdate: &schema.TaskStatusUpdateEvent{
+		ID:     "t1",
+		Status: schema.TaskStatus{State: schema.TaskStateCompleted},
+		Final:  true,
+	}})
+
+	ev := <-events
+	if !ev.Final() {
+		t.Fatalf("first delivered event not final: %+v", ev)
+	}
+	if _, ok := <-events; ok {
+		t.Fatal("events not closed after a Final event")
+	}
+
+	cancel()
+
+	if got := pubSubLen(p); got != 0 {
+		t.Errorf("broadcasts len = %d after the last subscriber detached past a Final event, want 0 (leaked entry)", got)
+	}
+}
+
+// TestPubSubSubscribeAfterEvictionGetsFreshBroadcast proves a new
+// Subscribe for the same task id after eviction isn't silently dropped
+// by a racing evict call — it must see its own events, not a broadcast
+// that's already gone.
+func TestPubSubSubscribeAfterEvictionGetsFreshBroadcast(t *testing.T) {
+	p := NewPubSub()
+
+	events1, cancel1 := p.Subscribe("t1")
+	p.Publish("t1", TaskEvent{StatusUpdate: &schema.TaskStatusUpdateEvent{
+		ID: "t1", Status: schema.TaskStatus{State: schema.TaskStateCompleted}, Final: true,
+	}})
+	<-events1
+	cancel1()
+
+	if got := pubSubLen(p); got != 0 {
+		t.Fatalf("broadcasts len = %d after eviction, want 0", got)
+	}
+
+	events2, cancel2 := p.Subscribe("t1")
+	defer cancel2()
+	p.Publish("t1", TaskEvent{StatusUpdate: &schema.TaskStatusUpdateEvent{
+		ID: "t1", Status: schema.TaskStatus{State: schema.TaskStateWorking},
+	}})
+
+	ev := <-events2
+	if ev.StatusUpdate == nil || ev.StatusUpdate.Status.State != schema.TaskStateWorking {
+		t.Fatalf("second subscriber got %+v, want the working update published after resubscribing", ev)
+	}
+}
@@ -0,0 +1,213 @@
+// Package redis implements store.TaskStore on top of Redis: task state
+// lives in a hash per task, history in a length-capped list, and a set
+// tracks known task IDs so List can enumerate them.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/google/A2A/samples/go/schema"
+	"github.com/google/A2A/samples/go/store"
+)
+
+// historyLimit bounds how many messages are kept per task; older entries
+// are trimmed off as new ones are appended.
+const historyLimit = 1000
+
+// TaskStore implements store.TaskStore on a Redis client. Like sql.TaskStore,
+// tasks/sendSubscribe fan-out happens in-process via an embedded
+// *store.PubSub rather than through Redis pub/sub.
+type TaskStore struct {
+	client *goredis.Client
+	*store.PubSub
+}
+
+// New creates a TaskStore backed by client.
+func New(client *goredis.Client) *TaskStore {
+	return &TaskStore{client: client, PubSub: store.NewPubSub()}
+}
+
+func taskKey(id string) string       { return "task:" + id }
+func historyKey(id string) string    { return "task_history:" + id }
+func pushConfigKey(id string) string { return "push_config:" + id }
+
+const taskIndexKey = "tasks"
+
+// Get retrieves a task by its ID.
+func (s *TaskStore) Get(id string) (*schema.Task, bool) {
+	task, err := s.get(context.Background(), id)
+	if err != nil || task == nil {
+		return nil, false
+	}
+	return task, true
+}
+
+func (s *TaskStore) get(ctx context.Context, id string) (*schema.Task, error) {
+	fields, err := s.client.HGetAll(ctx, taskKey(id)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis: get task %q: %w", id, err)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	task := &schema.Task{ID: id}
+	if sessionID, ok := fields["sessionId"]; ok {
+		task.SessionID = &sessionID
+	}
+	if status, ok := fields["status"]; ok {
+		if err := json.Unmarshal([]byte(status), &task.Status); err != nil {
+			return nil, fmt.Errorf("redis: decode status for task %q: %w", id, err)
+		}
+	}
+	if artifacts, ok := fields["artifacts"]; ok {
+		if err := json.Unmarshal([]byte(artifacts), &task.Artifacts); err != nil {
+			return nil, fmt.Errorf("redis: decode artifacts for task %q: %w", id, err)
+		}
+	}
+	if metadata, ok := fields["metadata"]; ok {
+		if err := json.Unmarshal([]byte(metadata), &task.Metadata); err != nil {
+			return nil, fmt.Errorf("redis: decode metadata for task %q: %w", id, err)
+		}
+	}
+	return task, nil
+}
+
+// Put stores task, overwriting any existing hash for its ID. Like
+// InMemoryTaskStore, Put has no error return; a write failure is
+// dropped rather than surfaced.
+func (s *TaskStore) Put(task *schema.Task) {
+	ctx := context.Background()
+	fields := map[string]interface{}{}
+
+	if statusJSON, err := json.Marshal(task.Status); err == nil {
+		fields["status"] = string(statusJSON)
+	}
+	if task.SessionID != nil {
+		fields["sessionId"] = *task.SessionID
+	}
+	if task.Artifacts != nil {
+		if artifactsJSON, err := json.Marshal(task.Artifacts); err == nil {
+			fields["artifacts"] = string(artifactsJSON)
+		}
+	}
+	if task.Metadata != nil {
+		if metadataJSON, err := json.Marshal(task.Metadata); err == nil {
+			fields["metadata"] = string(metadataJSON)
+		}
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, taskKey(task.ID))
+	pipe.HSet(ctx, taskKey(task.ID), fields)
+	pipe.SAdd(ctx, taskIndexKey, task.ID)
+	_, _ = pipe.Exec(ctx)
+}
+
+// List returns the tasks matching filter.
+func (s *TaskStore) List(filter store.TaskFilter) ([]*schema.Task, error) {
+	ctx := context.Background()
+	ids, err := s.client.SMembers(ctx, taskIndexKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis: list task ids: %w", err)
+	}
+
+	var tasks []*schema.Task
+	for _, id := range ids {
+		task, err := s.get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if task == nil {
+			continue
+		}
+		if filter.SessionID != nil && (task.SessionID == nil || *task.SessionID != *filter.SessionID) {
+			continue
+		}
+		if filter.State != nil && task.Status.State != *filter.State {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// Delete removes a task along with its history and push config.
+func (s *TaskStore) Delete(id string) error {
+	ctx := context.Background()
+	pipe := s.client.TxPipeline()
+	pipe.Del(ctx, taskKey(id), historyKey(id), pushConfigKey(id))
+	pipe.SRem(ctx, taskIndexKey, id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis: delete task %q: %w", id, err)
+	}
+	return nil
+}
+
+// AppendHistory records msg as the next message in task id's history,
+// trimming the list to the most recent historyLimit entries.
+func (s *TaskStore) AppendHistory(id string, msg schema.Message) error {
+	msgJSON, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("redis: encode history message for task %q: %w", id, err)
+	}
+
+	ctx := context.Background()
+	pipe := s.client.TxPipeline()
+	pipe.RPush(ctx, historyKey(id), string(msgJSON))
+	pipe.LTrim(ctx, historyKey(id), -historyLimit, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("redis: append history for task %q: %w", id, err)
+	}
+	return nil
+}
+
+// History returns the last limit messages recorded for task id.
+func (s *TaskStore) History(id string, limit int) ([]schema.Message, error) {
+	ctx := context.Background()
+	start := int64(0)
+	if limit > 0 {
+		start = -int64(limit)
+	}
+	raw, err := s.client.LRange(ctx, historyKey(id), start, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis: history for task %q: %w", id, err)
+	}
+
+	msgs := make([]schema.Message, 0, len(raw))
+	for _, entry := range raw {
+		var msg schema.Message
+		if err := json.Unmarshal([]byte(entry), &msg); err != nil {
+			return nil, fmt.Errorf("redis: decode history message for task %q: %w", id, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// SetPushConfig stores the push notification config registered for id.
+// Like Put, a write failure is dropped rather than surfaced.
+func (s *TaskStore) SetPushConfig(id string, cfg schema.PushNotificationConfig) {
+	cfgJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return
+	}
+	s.client.Set(context.Background(), pushConfigKey(id), string(cfgJSON), 0)
+}
+
+// GetPushConfig retrieves the push notification config registered for id.
+func (s *TaskStore) GetPushConfig(id string) (schema.PushNotificationConfig, bool) {
+	raw, err := s.client.Get(context.Background(), pushConfigKey(id)).Result()
+	if err != nil {
+		return schema.PushNotificationConfig{}, false
+	}
+	var cfg schema.PushNotificationConfig
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return schema.PushNotificationConfig{}, false
+	}
+	return cfg, true
+}
@@ -0,0 +1,33 @@
+package redis_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/google/A2A/samples/go/store"
+	redisstore "github.com/google/A2A/samples/go/store/redis"
+	"github.com/google/A2A/samples/go/store/storetest"
+)
+
+// TestTaskStoreConformance runs the shared conformance suite against a
+// real Redis instance named by TASKSTORE_REDIS_ADDR. It's skipped when
+// that variable isn't set, since the sandbox this sample normally runs
+// in has no live Redis instance.
+func TestTaskStoreConformance(t *testing.T) {
+	addr := os.Getenv("TASKSTORE_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TASKSTORE_REDIS_ADDR not set; skipping Redis conformance test")
+	}
+
+	storetest.Run(t, func(t *testing.T) store.TaskStore {
+		client := goredis.NewClient(&goredis.Options{Addr: addr})
+		t.Cleanup(func() { client.Close() })
+		if err := client.FlushDB(context.Background()).Err(); err != nil {
+			t.Fatalf("FlushDB: %v", err)
+		}
+		return redisstore.New(client)
+	})
+}
@@ -0,0 +1,313 @@
+// Package sql implements store.TaskStore on top of database/sql. It is
+// written against PostgreSQL's dialect (placeholders, upserts), using
+// github.com/lib/pq as the driver a caller registers by importing it.
+package sql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/A2A/samples/go/schema"
+	"github.com/google/A2A/samples/go/store"
+)
+
+// migrations creates the tables TaskStore needs if they don't already
+// exist. It's safe to run on every startup.
+const migrations = `
+CREATE TABLE IF NOT EXISTS tasks (
+	id         TEXT PRIMARY KEY,
+	session_id TEXT,
+	status     TEXT NOT NULL,
+	metadata   TEXT
+);
+
+CREATE TABLE IF NOT EXISTS task_artifacts (
+	task_id  TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	idx      INTEGER NOT NULL,
+	artifact TEXT NOT NULL,
+	PRIMARY KEY (task_id, idx)
+);
+
+CREATE TABLE IF NOT EXISTS task_history (
+	task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+	seq     BIGSERIAL PRIMARY KEY,
+	message TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS push_configs (
+	task_id TEXT PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+	config  TEXT NOT NULL
+);
+`
+
+// TaskStore implements store.TaskStore on a *sql.DB. Put, AppendHistory
+// and the pub/sub methods rely on the database only for durability;
+// fan-out to tasks/sendSubscribe and tasks/resubscribe subscribers still
+// happens in-process via an embedded *store.PubSub, the same as
+// InMemoryTaskStore.
+type TaskStore struct {
+	db *sql.DB
+	*store.PubSub
+}
+
+// Open creates a TaskStore backed by db, running migrations first.
+func Open(db *sql.DB) (*TaskStore, error) {
+	if _, err := db.Exec(migrations); err != nil {
+		return nil, fmt.Errorf("sql: running migrations: %w", err)
+	}
+	return &TaskStore{db: db, PubSub: store.NewPubSub()}, nil
+}
+
+// Get retrieves a task by its ID, including its artifacts.
+func (s *TaskStore) Get(id string) (*schema.Task, bool) {
+	task, err := s.get(id)
+	if err != nil {
+		return nil, false
+	}
+	return task, task != nil
+}
+
+func (s *TaskStore) get(id string) (*schema.Task, error) {
+	var sessionID sql.NullString
+	var statusJSON, metadataJSON sql.NullString
+	row := s.db.QueryRow(`SELECT session_id, status, metadata FROM tasks WHERE id = $1`, id)
+	if err := row.Scan(&sessionID, &statusJSON, &metadataJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("sql: get task %q: %w", id, err)
+	}
+
+	task := &schema.Task{ID: id}
+	if sessionID.Valid {
+		task.SessionID = &sessionID.String
+	}
+	if statusJSON.Valid {
+		if err := json.Unmarshal([]byte(statusJSON.String), &task.Status); err != nil {
+			return nil, fmt.Errorf("sql: decode status for task %q: %w", id, err)
+		}
+	}
+	if metadataJSON.Valid {
+		if err := json.Unmarshal([]byte(metadataJSON.String), &task.Metadata); err != nil {
+			return nil, fmt.Errorf("sql: decode metadata for task %q: %w", id, err)
+		}
+	}
+
+	artifacts, err := s.artifacts(id)
+	if err != nil {
+		return nil, err
+	}
+	task.Artifacts = artifacts
+	return task, nil
+}
+
+func (s *TaskStore) artifacts(taskID string) ([]schema.Artifact, error) {
+	rows, err := s.db.Query(`SELECT artifact FROM task_artifacts WHERE task_id = $1 ORDER BY idx`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("sql: list artifacts for task %q: %w", taskID, err)
+	}
+	defer rows.Close()
+
+	var artifacts []schema.Artifact
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("sql: scan artifact for task %q: %w", taskID, err)
+		}
+		var artifact schema.Artifact
+		if err := json.Unmarshal([]byte(raw), &artifact); err != nil {
+			return nil, fmt.Errorf("sql: decode artifact for task %q: %w", taskID, err)
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	return artifacts, rows.Err()
+}
+
+// Put stores task, overwriting any existing row and its artifacts.
+func (s *TaskStore) Put(task *schema.Task) {
+	if err := s.put(task); err != nil {
+		// TaskStore.Put has no error return (it matches InMemoryTaskStore's
+		// signature), so a write failure is dropped rather than surfaced.
+		_ = err
+	}
+}
+
+func (s *TaskStore) put(task *schema.Task) error {
+	statusJSON, err := json.Marshal(task.Status)
+	if err != nil {
+		return fmt.Errorf("sql: encode status for task %q: %w", task.ID, err)
+	}
+	var metadataJSON []byte
+	if task.Metadata != nil {
+		metadataJSON, err = json.Marshal(task.Metadata)
+		if err != nil {
+			return fmt.Errorf("sql: encode metadata for task %q: %w", task.ID, err)
+		}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("sql: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO tasks (id, session_id, status, metadata)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET session_id = $2, status = $3, metadata = $4
+	`, task.ID, task.SessionID, string(statusJSON), nullableString(metadataJSON))
+	if err != nil {
+		return fmt.Errorf("sql: upsert task %q: %w", task.ID, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM task_artifacts WHERE task_id = $1`, task.ID); err != nil {
+		return fmt.Errorf("sql: clear artifacts for task %q: %w", task.ID, err)
+	}
+	for i, artifact := range task.Artifacts {
+		artifactJSON, err := json.Marshal(artifact)
+		if err != nil {
+			return fmt.Errorf("sql: encode artifact %d for task %q: %w", i, task.ID, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO task_artifacts (task_id, idx, artifact) VALUES ($1, $2, $3)`,
+			task.ID, i, string(artifactJSON)); err != nil {
+			return fmt.Errorf("sql: insert artifact %d for task %q: %w", i, task.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func nullableString(b []byte) interface{} {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
+
+// List returns the tasks matching filter.
+func (s *TaskStore) List(filter store.TaskFilter) ([]*schema.Task, error) {
+	query := `SELECT id FROM tasks WHERE 1=1`
+	var args []interface{}
+	if filter.SessionID != nil {
+		args = append(args, *filter.SessionID)
+		query += fmt.Sprintf(" AND session_id = $%d", len(args))
+	}
+	// status is stored as a JSON blob, so filter.State is applied below
+	// after decoding each candidate row rather than in the WHERE clause.
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sql: list tasks: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("sql: scan task id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var tasks []*schema.Task
+	for _, id := range ids {
+		task, err := s.get(id)
+		if err != nil {
+			return nil, err
+		}
+		if task == nil {
+			continue
+		}
+		if filter.State != nil && task.Status.State != *filter.State {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// Delete removes a task; ON DELETE CASCADE takes care of its artifacts,
+// history and push config.
+func (s *TaskStore) Delete(id string) error {
+	if _, err := s.db.Exec(`DELETE FROM tasks WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("sql: delete task %q: %w", id, err)
+	}
+	return nil
+}
+
+// AppendHistory records msg as the next message in task id's history.
+func (s *TaskStore) AppendHistory(id string, msg schema.Message) error {
+	msgJSON, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("sql: encode history message for task %q: %w", id, err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO task_history (task_id, message) VALUES ($1, $2)`, id, string(msgJSON)); err != nil {
+		return fmt.Errorf("sql: append history for task %q: %w", id, err)
+	}
+	return nil
+}
+
+// History returns the last limit messages recorded for task id.
+func (s *TaskStore) History(id string, limit int) ([]schema.Message, error) {
+	query := `SELECT message FROM task_history WHERE task_id = $1 ORDER BY seq`
+	args := []interface{}{id}
+	if limit > 0 {
+		query = `
+			SELECT message FROM (
+				SELECT message, seq FROM task_history WHERE task_id = $1 ORDER BY seq DESC LIMIT $2
+			) recent ORDER BY seq`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sql: history for task %q: %w", id, err)
+	}
+	defer rows.Close()
+
+	var msgs []schema.Message
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("sql: scan history message for task %q: %w", id, err)
+		}
+		var msg schema.Message
+		if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+			return nil, fmt.Errorf("sql: decode history message for task %q: %w", id, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, rows.Err()
+}
+
+// SetPushConfig stores the push notification config registered for id.
+// Like Put, it matches TaskStore's error-less signature; a write failure
+// is dropped rather than surfaced (see Put).
+func (s *TaskStore) SetPushConfig(id string, cfg schema.PushNotificationConfig) {
+	cfgJSON, err := json.Marshal(cfg)
+	if err != nil {
+		return
+	}
+	_, _ = s.db.Exec(`
+		INSERT INTO push_configs (task_id, config) VALUES ($1, $2)
+		ON CONFLICT (task_id) DO UPDATE SET config = $2
+	`, id, string(cfgJSON))
+}
+
+// GetPushConfig retrieves the push notification config registered for id.
+func (s *TaskStore) GetPushConfig(id string) (schema.PushNotificationConfig, bool) {
+	var raw string
+	row := s.db.QueryRow(`SELECT config FROM push_configs WHERE task_id = $1`, id)
+	if err := row.Scan(&raw); err != nil {
+		return schema.PushNotificationConfig{}, false
+	}
+	var cfg schema.PushNotificationConfig
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return schema.PushNotificationConfig{}, false
+	}
+	return cfg, true
+}
@@ -0,0 +1,43 @@
+package sql_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+
+	"github.com/google/A2A/samples/go/store"
+	sqlstore "github.com/google/A2A/samples/go/store/sql"
+	"github.com/google/A2A/samples/go/store/storetest"
+)
+
+// TestTaskStoreConformance runs the shared conformance suite against a
+// real Postgres database named by TASKSTORE_POSTGRES_DSN. It's skipped
+// when that variable isn't set, since the sandbox this sample normally
+// runs in has no live Postgres instance.
+func TestTaskStoreConformance(t *testing.T) {
+	dsn := os.Getenv("TASKSTORE_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TASKSTORE_POSTGRES_DSN not set; skipping Postgres conformance test")
+	}
+
+	storetest.Run(t, func(t *testing.T) store.TaskStore {
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			t.Fatalf("sql.Open: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		s, err := sqlstore.Open(db)
+		if err != nil {
+			t.Fatalf("sqlstore.Open: %v", err)
+		}
+		for _, table := range []string{"task_artifacts", "task_history", "push_configs", "tasks"} {
+			if _, err := db.Exec("DELETE FROM " + table); err != nil {
+				t.Fatalf("clearing table %q: %v", table, err)
+			}
+		}
+		return s
+	})
+}
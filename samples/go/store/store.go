@@ -0,0 +1,49 @@
+package store
+
+import (
+	"github.com/google/A2A/samples/go/schema"
+)
+
+// TaskFilter narrows a List call. A nil or zero-valued field is not
+// applied as a constraint.
+type TaskFilter struct {
+	// SessionID, if set, restricts the results to tasks with this
+	// session ID.
+	SessionID *string
+	// State, if set, restricts the results to tasks currently in this
+	// state.
+	State *schema.TaskState
+}
+
+// TaskStore defines the interface for storing and retrieving A2A tasks.
+// Implementations must make Put safe for concurrent use.
+type TaskStore interface {
+	Get(id string) (*schema.Task, bool)
+	Put(task *schema.Task)
+	// List returns the tasks matching filter.
+	List(filter TaskFilter) ([]*schema.Task, error)
+	// Delete removes a task along with its history, artifacts and push
+	// config. It is not an error to delete an ID that doesn't exist.
+	Delete(id string) error
+
+	// AppendHistory records msg as the next message in task id's
+	// history.
+	AppendHistory(id string, msg schema.Message) error
+	// History returns the last limit messages recorded for task id, in
+	// chronological order. A non-positive limit returns the entire
+	// history.
+	History(id string, limit int) ([]schema.Message, error)
+
+	// Subscribe attaches a listener to task id's event stream for
+	// tasks/sendSubscribe and tasks/resubscribe. See PubSub.Subscribe.
+	Subscribe(id string) (events <-chan TaskEvent, cancel func())
+	// Publish fans ev out to every subscriber currently attached to id.
+	Publish(id string, ev TaskEvent)
+
+	// SetPushConfig stores the push notification config a client
+	// registered for task id via tasks/pushNotification/set.
+	SetPushConfig(id string, cfg schema.PushNotificationConfig)
+	// GetPushConfig retrieves the push notification config registered
+	// for task id, if any.
+	GetPushConfig(id string) (schema.PushNotificationConfig, bool)
+}
@@ -0,0 +1,63 @@
+// Package storeopen constructs a store.TaskStore from a DSN, dispatching
+// on its scheme (memory://, postgres://, redis://) so main can pick a
+// backend with a single flag instead of wiring each driver up by hand.
+package storeopen
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	_ "github.com/lib/pq"
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/google/A2A/samples/go/store"
+	redisstore "github.com/google/A2A/samples/go/store/redis"
+	sqlstore "github.com/google/A2A/samples/go/store/sql"
+)
+
+// TaskStoreFactory builds a store.TaskStore from a full DSN, scheme
+// included (e.g. "postgres://..."), which is what Open passes it.
+type TaskStoreFactory func(dsn string) (store.TaskStore, error)
+
+// factories maps a DSN scheme to the factory that handles it.
+var factories = map[string]TaskStoreFactory{
+	"memory":   openMemory,
+	"postgres": openPostgres,
+	"redis":    openRedis,
+}
+
+// Open constructs a store.TaskStore from dsn. The scheme selects the
+// backend: "memory://" (the DSN body is ignored), "postgres://...", or
+// "redis://...".
+func Open(dsn string) (store.TaskStore, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("storeopen: invalid DSN %q: %w", dsn, err)
+	}
+	factory, ok := factories[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("storeopen: unsupported store scheme %q", u.Scheme)
+	}
+	return factory(dsn)
+}
+
+func openMemory(string) (store.TaskStore, error) {
+	return store.NewInMemoryTaskStore(), nil
+}
+
+func openPostgres(dsn string) (store.TaskStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("storeopen: opening postgres DSN: %w", err)
+	}
+	return sqlstore.Open(db)
+}
+
+func openRedis(dsn string) (store.TaskStore, error) {
+	opts, err := goredis.ParseURL(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("storeopen: invalid redis DSN: %w", err)
+	}
+	return redisstore.New(goredis.NewClient(opts)), nil
+}
@@ -0,0 +1,235 @@
+// Package storetest holds a conformance suite shared by every
+// store.TaskStore implementation, so each backend (in-memory, SQL,
+// Redis) is tested against the same behavioral contract.
+package storetest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/A2A/samples/go/schema"
+	"github.com/google/A2A/samples/go/store"
+)
+
+func strPtr(s string) *string                       { return &s }
+func statePtr(s schema.TaskState) *schema.TaskState { return &s }
+
+func textMessage(role, text string) schema.Message {
+	return schema.Message{Role: role, Parts: []schema.Part{schema.TextPart{Type: "text", Text: text}}}
+}
+
+// Run exercises new() against the TaskStore conformance suite. new must
+// return a fresh, empty TaskStore each time it's called.
+func Run(t *testing.T, new func(t *testing.T) store.TaskStore) {
+	t.Helper()
+	tests := map[string]func(t *testing.T, s store.TaskStore){
+		"GetMissing":        testGetMissing,
+		"PutThenGet":        testPutThenGet,
+		"PutOverwrites":     testPutOverwrites,
+		"List":              testList,
+		"Delete":            testDelete,
+		"History":           testHistory,
+		"HistoryLimit":      testHistoryLimit,
+		"PushConfig":        testPushConfig,
+		"PushConfigMissing": testPushConfigMissing,
+		"PubSub":            testPubSub,
+		"ConcurrentPut":     testConcurrentPut,
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, new(t))
+		})
+	}
+}
+
+func testGetMissing(t *testing.T, s store.TaskStore) {
+	if _, ok := s.Get("missing"); ok {
+		t.Fatal("Get on an unknown task should report false")
+	}
+}
+
+func testPutThenGet(t *testing.T, s store.TaskStore) {
+	task := &schema.Task{
+		ID:        "task-1",
+		SessionID: strPtr("session-1"),
+		Status:    schema.TaskStatus{State: schema.TaskStateSubmitted},
+		Metadata:  map[string]interface{}{"k": "v"},
+	}
+	s.Put(task)
+
+	got, ok := s.Get("task-1")
+	if !ok {
+		t.Fatal("expected to find task-1")
+	}
+	if got.ID != task.ID || got.Status.State != task.Status.State {
+		t.Fatalf("got %+v, want %+v", got, task)
+	}
+	if got.SessionID == nil || *got.SessionID != "session-1" {
+		t.Fatalf("SessionID not round-tripped: %+v", got)
+	}
+}
+
+func testPutOverwrites(t *testing.T, s store.TaskStore) {
+	s.Put(&schema.Task{ID: "task-1", Status: schema.TaskStatus{State: schema.TaskStateSubmitted}})
+	s.Put(&schema.Task{ID: "task-1", Status: schema.TaskStatus{State: schema.TaskStateCompleted}})
+
+	got, ok := s.Get("task-1")
+	if !ok {
+		t.Fatal("expected to find task-1")
+	}
+	if got.Status.State != schema.TaskStateCompleted {
+		t.Fatalf("Put did not overwrite: got state %q", got.Status.State)
+	}
+}
+
+func testList(t *testing.T, s store.TaskStore) {
+	s.Put(&schema.Task{ID: "a", SessionID: strPtr("s1"), Status: schema.TaskStatus{State: schema.TaskStateWorking}})
+	s.Put(&schema.Task{ID: "b", SessionID: strPtr("s1"), Status: schema.TaskStatus{State: schema.TaskStateCompleted}})
+	s.Put(&schema.Task{ID: "c", SessionID: strPtr("s2"), Status: schema.TaskStatus{State: schema.TaskStateCompleted}})
+
+	all, err := s.List(store.TaskFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("List() returned %d tasks, want 3", len(all))
+	}
+
+	bySession, err := s.List(store.TaskFilter{SessionID: strPtr("s1")})
+	if err != nil {
+		t.Fatalf("List by session: %v", err)
+	}
+	if len(bySession) != 2 {
+		t.Fatalf("List(session=s1) returned %d tasks, want 2", len(bySession))
+	}
+
+	byState, err := s.List(store.TaskFilter{State: statePtr(schema.TaskStateCompleted)})
+	if err != nil {
+		t.Fatalf("List by state: %v", err)
+	}
+	if len(byState) != 2 {
+		t.Fatalf("List(state=completed) returned %d tasks, want 2", len(byState))
+	}
+}
+
+func testDelete(t *testing.T, s store.TaskStore) {
+	s.Put(&schema.Task{ID: "task-1", Status: schema.TaskStatus{State: schema.TaskStateSubmitted}})
+	if err := s.AppendHistory("task-1", textMessage("user", "hi")); err != nil {
+		t.Fatalf("AppendHistory: %v", err)
+	}
+	s.SetPushConfig("task-1", schema.PushNotificationConfig{URL: "https://example.com"})
+
+	if err := s.Delete("task-1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := s.Get("task-1"); ok {
+		t.Fatal("task-1 should be gone after Delete")
+	}
+	if hist, err := s.History("task-1", 0); err != nil || len(hist) != 0 {
+		t.Fatalf("History after Delete = %v, %v; want empty, nil", hist, err)
+	}
+	if _, ok := s.GetPushConfig("task-1"); ok {
+		t.Fatal("push config should be gone after Delete")
+	}
+
+	// Deleting an unknown ID is not an error.
+	if err := s.Delete("never-existed"); err != nil {
+		t.Fatalf("Delete on unknown ID: %v", err)
+	}
+}
+
+func testHistory(t *testing.T, s store.TaskStore) {
+	if err := s.AppendHistory("task-1", textMessage("user", "one")); err != nil {
+		t.Fatalf("AppendHistory: %v", err)
+	}
+	if err := s.AppendHistory("task-1", textMessage("agent", "two")); err != nil {
+		t.Fatalf("AppendHistory: %v", err)
+	}
+
+	hist, err := s.History("task-1", 0)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(hist) != 2 {
+		t.Fatalf("History returned %d messages, want 2", len(hist))
+	}
+	if len(hist[0].Parts) == 0 || len(hist[1].Parts) == 0 {
+		t.Fatalf("History messages lost their parts: %+v", hist)
+	}
+}
+
+func testHistoryLimit(t *testing.T, s store.TaskStore) {
+	for i := 0; i < 5; i++ {
+		if err := s.AppendHistory("task-1", textMessage("user", string(rune('a'+i)))); err != nil {
+			t.Fatalf("AppendHistory: %v", err)
+		}
+	}
+
+	last2, err := s.History("task-1", 2)
+	if err != nil {
+		t.Fatalf("History(limit=2): %v", err)
+	}
+	if len(last2) != 2 {
+		t.Fatalf("History(limit=2) returned %d messages, want 2", len(last2))
+	}
+	gotText := last2[1].Parts[0].(schema.TextPart).Text
+	if gotText != "e" {
+		t.Fatalf("last message text = %q, want %q", gotText, "e")
+	}
+}
+
+func testPushConfig(t *testing.T, s store.TaskStore) {
+	cfg := schema.PushNotificationConfig{URL: "https://example.com/hook"}
+	s.SetPushConfig("task-1", cfg)
+
+	got, ok := s.GetPushConfig("task-1")
+	if !ok {
+		t.Fatal("expected to find push config for task-1")
+	}
+	if got.URL != cfg.URL {
+		t.Fatalf("got URL %q, want %q", got.URL, cfg.URL)
+	}
+}
+
+func testPushConfigMissing(t *testing.T, s store.TaskStore) {
+	if _, ok := s.GetPushConfig("missing"); ok {
+		t.Fatal("GetPushConfig on an unknown task should report false")
+	}
+}
+
+func testPubSub(t *testing.T, s store.TaskStore) {
+	events, cancel := s.Subscribe("task-1")
+	defer cancel()
+
+	s.Publish("task-1", store.TaskEvent{StatusUpdate: &schema.TaskStatusUpdateEvent{
+		ID:     "task-1",
+		Status: schema.TaskStatus{State: schema.TaskStateCompleted},
+		Final:  true,
+	}})
+
+	select {
+	case ev := <-events:
+		if ev.StatusUpdate == nil || ev.StatusUpdate.Status.State != schema.TaskStateCompleted {
+			t.Fatalf("got unexpected event: %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("did not receive the published event in time")
+	}
+}
+
+func testConcurrentPut(t *testing.T, s store.TaskStore) {
+	const n = 50
+	done := make(chan struct{}, n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			defer func() { done <- struct{}{} }()
+			s.Put(&schema.Task{ID: "task-1", Status: schema.TaskStatus{State: schema.TaskStateWorking}})
+		}(i)
+	}
+	for i := 0; i < n; i++ {
+		<-done
+	}
+	if _, ok := s.Get("task-1"); !ok {
+		t.Fatal("expected task-1 to exist after concurrent Put")
+	}
+}